@@ -59,10 +59,17 @@ for i in $(seq 0 $((PROCESS_COUNT - 1))); do
     # 计算端口：进程i使用端口 8000 + i
     PORT_START=$((8000 + i * PORTS_PER_PROCESS))
     
-    echo "🎬 启动进程 $i: 端口 $PORT_START"
+    # 每个进程负责 [PROC_FROM, PROC_TO) 子范围，最后一个进程补齐余数
+    PROC_FROM=$((START_SLOT + i * BLOCKS_PER_PROCESS))
+    PROC_TO=$((PROC_FROM + BLOCKS_PER_PROCESS))
+    if [ $i -eq $((PROCESS_COUNT - 1)) ]; then
+        PROC_TO=$END_SLOT
+    fi
+
+    echo "🎬 启动进程 $i: 区块 $PROC_FROM - $PROC_TO"
     
     # 直接在后台运行，不重定向到日志文件
-    go run ./src/main.go $i $START_SLOT $END_SLOT $CYCLE_SIZE $BATCH_SIZE $PORT_START $PROCESS_COUNT &
+    go run ./src scan --from $PROC_FROM --to $PROC_TO --cycle $CYCLE_SIZE --batch $BATCH_SIZE &
     
     # 📊 检查CPU负载，如果过高则暂停
     LOAD_AVG=$(uptime | awk -F'load average:' '{ print $2 }' | awk '{ print $1 }' | sed 's/,//')
@@ -82,7 +89,7 @@ echo ""
 echo "📈 实时监控 (每60秒更新一次):"
 while true; do
     # 检查是否还有进程在运行
-    if ! pgrep -f "go run ./src/main.go" > /dev/null; then
+    if ! pgrep -f "go run ./src scan" > /dev/null; then
         echo "🎉 所有进程已完成!"
         break
     fi
@@ -94,7 +101,7 @@ while true; do
     
     # 📊 获取系统状态
     LOAD_AVG=$(uptime | awk -F'load average:' '{ print $2 }' | awk '{ print $1 }' | sed 's/,//')
-    RUNNING_PROCESSES=$(pgrep -f "go run ./src/main.go" | wc -l)
+    RUNNING_PROCESSES=$(pgrep -f "go run ./src scan" | wc -l)
     
     echo "$(date '+%H:%M:%S') - 运行: ${ELAPSED_HOURS}h | 活跃进程: $RUNNING_PROCESSES/$PROCESS_COUNT | CPU负载: $LOAD_AVG"
     
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/db"
+	"github.com/go-solana-parse/src/processor"
+	"github.com/go-solana-parse/src/processor/user_report_processor"
+	"github.com/go-solana-parse/src/service"
+	"github.com/go-solana-parse/src/sink"
+	"github.com/go-solana-parse/src/solana"
+	"github.com/go-solana-parse/src/util"
+)
+
+// defaultConfigPath 默认配置文件路径
+const defaultConfigPath = "./config-yaml/config.yaml"
+
+const usage = `用法: go-solana-parse <子命令> [参数]
+
+子命令:
+  scan             倒序扫描区块范围并转发到 sink
+                   --from --to --cycle --batch --workers --sink --sink-path --api-key
+                   --archive <目录> 同时归档获取的区块，--replay <目录> 从归档回放
+  follow           跟随链上最新区块，实时转发到 sink
+                   --from --commitment --poll --batch --reorg-buffer --sink --sink-path --api-key
+  retry            重试 failed_slots_*.txt 中的失败区块
+                   --file (可重复，也可作为位置参数) --batch --attempts --sink --sink-path --api-key
+  report           生成用户报告，未指定地址时处理全部地址
+                   --address
+  prices backfill  按区块高度范围回填 SOL 价格
+                   --from --to
+
+所有子命令支持 --config 指定配置文件（默认 ./config-yaml/config.yaml），
+命令行未指定的参数使用配置文件中对应子命令的值。
+sink 可选 deno（Deno 解析服务）、clickhouse（Go 解析后写入 ClickHouse）、
+file（Go 解析后写入 --sink-path 指定的 NDJSON 文件）、stdout（Go 解析后输出 NDJSON）。
+`
+
+// runCLI 解析子命令并执行
+func runCLI(args []string) error {
+	if len(args) == 0 {
+		util.Logf(usage)
+		return fmt.Errorf("缺少子命令")
+	}
+
+	switch args[0] {
+	case "scan":
+		opts, err := parseScanOptions(args[1:])
+		if err != nil {
+			return err
+		}
+		return processor.ScanRange(opts)
+	case "follow":
+		return runFollowCommand(args[1:])
+	case "retry":
+		return runRetryCommand(args[1:])
+	case "report":
+		return runReportCommand(args[1:])
+	case "prices":
+		if len(args) < 2 || args[1] != "backfill" {
+			return fmt.Errorf("用法: prices backfill --from <区块高度> --to <区块高度>")
+		}
+		return runPricesBackfillCommand(args[2:])
+	case "help", "-h", "--help":
+		util.Logf(usage)
+		return nil
+	}
+
+	util.Logf(usage)
+	return fmt.Errorf("未知子命令: %s", args[0])
+}
+
+// parseScanOptions 解析 scan 子命令参数
+func parseScanOptions(args []string) (processor.ScanOptions, error) {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	from := fs.Uint64("from", 0, "起始区块（包含）")
+	to := fs.Uint64("to", 0, "结束区块（不包含）")
+	cycle := fs.Int("cycle", 100, "每个 cycle 的区块数")
+	batch := fs.Int("batch", 10, "单次批量 RPC 请求的区块数")
+	workers := fs.Int("workers", runtime.NumCPU(), "并发处理的 cycle 数")
+	sinkKind := fs.String("sink", sink.SinkDeno, "解析结果去向: deno、clickhouse、file 或 stdout")
+	sinkPath := fs.String("sink-path", "", "sink 为 file 时的 NDJSON 输出路径")
+	apiKey := fs.String("api-key", "", "Solana RPC API key")
+	archive := fs.String("archive", "", "获取的区块同时写入该归档目录")
+	replay := fs.String("replay", "", "从该归档目录回放区块，不发起 RPC 请求")
+
+	set, err := parseFlags(fs, args)
+	if err != nil {
+		return processor.ScanOptions{}, err
+	}
+
+	filter, err := configTransactionFilter()
+	if err != nil {
+		return processor.ScanOptions{}, err
+	}
+
+	cfg := config.SvcConfig.Scan
+	return processor.ScanOptions{
+		From:     pickUint64(set["from"], *from, cfg.From),
+		To:       pickUint64(set["to"], *to, cfg.To),
+		Cycle:    pickInt(set["cycle"], *cycle, cfg.Cycle),
+		Batch:    pickInt(set["batch"], *batch, cfg.Batch),
+		Workers:  pickInt(set["workers"], *workers, cfg.Workers),
+		Sink:     pickString(set["sink"], *sinkKind, cfg.Sink),
+		SinkPath: pickString(set["sink-path"], *sinkPath, cfg.SinkPath),
+		APIKey:   pickString(set["api-key"], *apiKey, config.SvcConfig.Solana.ApiKey),
+		Archive:  pickString(set["archive"], *archive, cfg.Archive),
+		Replay:   pickString(set["replay"], *replay, cfg.Replay),
+		Filter:   filter,
+	}, nil
+}
+
+// configTransactionFilter 根据配置文件 filter 段构建交易过滤器
+func configTransactionFilter() (solana.TransactionFilter, error) {
+	filter, err := solana.NewTransactionFilter(config.SvcConfig.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("filter 配置无效: %v", err)
+	}
+	return filter, nil
+}
+
+// openParseSink 创建 follow / retry 使用的 sink，命令行未指定时使用配置文件 scan 段的 sink
+func openParseSink(set map[string]bool, kind, path string) (sink.ParseSink, error) {
+	cfg := config.SvcConfig.Scan
+	out, err := sink.NewParseSink(pickString(set["sink"], kind, cfg.Sink), pickString(set["sink-path"], path, cfg.SinkPath))
+	if err != nil {
+		return nil, fmt.Errorf("创建 sink 失败: %v", err)
+	}
+	return out, nil
+}
+
+// runFollowCommand 执行 follow 子命令，收到 Ctrl+C / SIGTERM 时退出
+func runFollowCommand(args []string) error {
+	defaults := solana.DefaultFollowOptions("")
+
+	fs := flag.NewFlagSet("follow", flag.ContinueOnError)
+	from := fs.Uint64("from", 0, "起始 slot，0 表示从当前最新 slot 开始")
+	commitment := fs.String("commitment", defaults.Commitment, "确认级别: confirmed 或 finalized")
+	poll := fs.Duration("poll", defaults.PollInterval, "getSlot 轮询间隔")
+	batch := fs.Int("batch", defaults.BatchSize, "单次批量 RPC 请求的区块数")
+	reorgBuffer := fs.Int("reorg-buffer", defaults.ReorgBuffer, "暂缓输出的 slot 数")
+	apiKey := fs.String("api-key", "", "Solana RPC API key")
+	sinkKind := fs.String("sink", sink.SinkDeno, "解析结果去向: deno、clickhouse、file 或 stdout")
+	sinkPath := fs.String("sink-path", "", "sink 为 file 时的 NDJSON 输出路径")
+
+	set, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.SvcConfig.Follow
+	opts := solana.FollowOptions{
+		APIKey:       pickString(set["api-key"], *apiKey, config.SvcConfig.Solana.ApiKey),
+		Commitment:   pickString(set["commitment"], *commitment, cfg.Commitment),
+		StartSlot:    pickUint64(set["from"], *from, cfg.From),
+		PollInterval: *poll,
+		BatchSize:    pickInt(set["batch"], *batch, cfg.Batch),
+		ReorgBuffer:  pickInt(set["reorg-buffer"], *reorgBuffer, cfg.ReorgBuffer),
+	}
+	if !set["poll"] && cfg.PollInterval != "" {
+		if opts.PollInterval, err = time.ParseDuration(cfg.PollInterval); err != nil {
+			return fmt.Errorf("poll_interval 配置无效: %v", err)
+		}
+	}
+	if err := solana.RequireRPCEndpoint(opts.APIKey); err != nil {
+		return err
+	}
+	filter, err := configTransactionFilter()
+	if err != nil {
+		return err
+	}
+
+	out, err := openParseSink(set, *sinkKind, *sinkPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := processor.FollowChain(ctx, opts, filter, out); err != nil && err != context.Canceled {
+		return err
+	}
+	return nil
+}
+
+// runRetryCommand 执行 retry 子命令
+func runRetryCommand(args []string) error {
+	fs := flag.NewFlagSet("retry", flag.ContinueOnError)
+	var files stringList
+	fs.Var(&files, "file", "失败区块文件，可重复指定")
+	batch := fs.Int("batch", 10, "单次批量 RPC 请求的区块数")
+	attempts := fs.Int("attempts", 5, "每个区块最多尝试次数")
+	apiKey := fs.String("api-key", "", "Solana RPC API key")
+	sinkKind := fs.String("sink", sink.SinkDeno, "解析结果去向: deno、clickhouse、file 或 stdout")
+	sinkPath := fs.String("sink-path", "", "sink 为 file 时的 NDJSON 输出路径")
+
+	set, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	paths := append(files, fs.Args()...)
+	if len(paths) == 0 {
+		paths = config.SvcConfig.Retry.Files
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("用法: retry --file <failed_slots 文件> [更多文件...]")
+	}
+
+	opts := processor.DefaultRetryOptions(pickString(set["api-key"], *apiKey, config.SvcConfig.Solana.ApiKey))
+	opts.BatchSize = pickInt(set["batch"], *batch, config.SvcConfig.Scan.Batch)
+	opts.MaxAttempts = pickInt(set["attempts"], *attempts, config.SvcConfig.Retry.Attempts)
+	if err := solana.RequireRPCEndpoint(opts.APIKey); err != nil {
+		return err
+	}
+	if opts.Filter, err = configTransactionFilter(); err != nil {
+		return err
+	}
+	if opts.Sink, err = openParseSink(set, *sinkKind, *sinkPath); err != nil {
+		return err
+	}
+	defer opts.Sink.Close()
+
+	_, err = processor.RetryFailedSlotsFiles(paths, opts)
+	return err
+}
+
+// runReportCommand 执行 report 子命令
+func runReportCommand(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	address := fs.String("address", "", "钱包地址，为空时处理全部地址")
+
+	set, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := initDatabases(); err != nil {
+		return err
+	}
+
+	reportProcessor := user_report_processor.NewUserReportProcessor()
+	if target := pickString(set["address"], *address, config.SvcConfig.Report.Address); target != "" {
+		_, err := reportProcessor.ProcessSingleUserReport(target)
+		return err
+	}
+	return reportProcessor.ProcessAllUserReports()
+}
+
+// runPricesBackfillCommand 执行 prices backfill 子命令
+func runPricesBackfillCommand(args []string) error {
+	fs := flag.NewFlagSet("prices backfill", flag.ContinueOnError)
+	from := fs.Uint64("from", 0, "起始区块高度")
+	to := fs.Uint64("to", 0, "结束区块高度")
+
+	set, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	start := pickUint64(set["from"], *from, config.SvcConfig.Prices.From)
+	end := pickUint64(set["to"], *to, config.SvcConfig.Prices.To)
+	if end <= start {
+		return fmt.Errorf("区块高度范围无效: %d - %d", start, end)
+	}
+
+	if err := db.InitClickHouseV2(); err != nil {
+		return fmt.Errorf("初始化 ClickHouse 失败: %v", err)
+	}
+	return service.NewPriceService(db.ClickHouseClient).BatchCalculateAndStorePrices(start, end)
+}
+
+// initDatabases 初始化 MySQL 与 ClickHouse 连接
+func initDatabases() error {
+	if err := db.InitDB(); err != nil {
+		return fmt.Errorf("初始化 MySQL 失败: %v", err)
+	}
+	if err := db.InitClickHouseV2(); err != nil {
+		return fmt.Errorf("初始化 ClickHouse 失败: %v", err)
+	}
+	return nil
+}
+
+// parseFlags 解析子命令参数并加载配置文件，返回命令行中显式指定的参数名
+// 使用默认路径且配置文件不存在时只使用命令行参数
+func parseFlags(fs *flag.FlagSet, args []string) (map[string]bool, error) {
+	configPath := fs.String("config", defaultConfigPath, "配置文件路径")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if err := config.LoadSvcConfigFile(*configPath); err != nil {
+		if !os.IsNotExist(err) || set["config"] {
+			return nil, fmt.Errorf("加载配置文件失败: %v", err)
+		}
+		util.Logf("⚠️ 配置文件 %s 不存在，仅使用命令行参数\n", *configPath)
+	}
+	return set, nil
+}
+
+// pickUint64 命令行显式指定时使用命令行值，否则配置文件有值时使用配置值，最后使用命令行默认值
+func pickUint64(explicit bool, flagValue, configValue uint64) uint64 {
+	if explicit || configValue == 0 {
+		return flagValue
+	}
+	return configValue
+}
+
+// pickInt 同 pickUint64
+func pickInt(explicit bool, flagValue, configValue int) int {
+	if explicit || configValue == 0 {
+		return flagValue
+	}
+	return configValue
+}
+
+// pickString 同 pickUint64
+func pickString(explicit bool, flagValue, configValue string) string {
+	if explicit || configValue == "" {
+		return flagValue
+	}
+	return configValue
+}
+
+// stringList 可重复指定的字符串参数
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
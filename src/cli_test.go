@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+)
+
+func TestParseScanOptionsConfigFallback(t *testing.T) {
+	*config.SvcConfig = config.Config{}
+	defer func() { *config.SvcConfig = config.Config{} }()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "solana:\n  api_key: key-from-config\nscan:\n  from: 100\n  to: 200\n  batch: 20\n  sink: deno\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := parseScanOptions([]string{"--config", path, "--to", "300", "--workers", "4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opts.From != 100 || opts.To != 300 {
+		t.Errorf("range = %d - %d, want 100 - 300", opts.From, opts.To)
+	}
+	if opts.Batch != 20 || opts.Workers != 4 || opts.Cycle != 100 {
+		t.Errorf("batch/workers/cycle = %d/%d/%d, want 20/4/100", opts.Batch, opts.Workers, opts.Cycle)
+	}
+	if opts.APIKey != "key-from-config" || opts.Sink != "deno" {
+		t.Errorf("api key/sink = %s/%s", opts.APIKey, opts.Sink)
+	}
+	if err := opts.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestParseScanOptionsMissingExplicitConfig(t *testing.T) {
+	*config.SvcConfig = config.Config{}
+	defer func() { *config.SvcConfig = config.Config{} }()
+
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	if _, err := parseScanOptions([]string{"--config", missing}); err == nil {
+		t.Fatal("expected error for missing explicit config file")
+	}
+}
+
+func TestParseScanOptionsEndpointsWithoutAPIKey(t *testing.T) {
+	*config.SvcConfig = config.Config{}
+	defer func() { *config.SvcConfig = config.Config{} }()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("scan:\n  from: 100\n  to: 200\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := parseScanOptions([]string{"--config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := opts.Validate(); err == nil {
+		t.Fatal("Validate() = nil, want error without api key or endpoints")
+	}
+
+	config.SvcConfig.Solana.Endpoints = []config.EndpointConfig{{URL: "https://rpc.example.com"}}
+	if err := opts.Validate(); err != nil {
+		t.Errorf("Validate() with endpoints = %v", err)
+	}
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/go-solana-parse/src/util"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,6 +16,12 @@ type Config struct {
 	ClickHouse ClickHouseConfig `yaml:"clickhouse"`
 	Solana     SolanaConfig     `yaml:"solana"`
 	RpcCall    RpcCallConfig    `yaml:"rpc_call"`
+	Scan       ScanConfig       `yaml:"scan"`
+	Retry      RetryConfig      `yaml:"retry"`
+	Follow     FollowConfig     `yaml:"follow"`
+	Report     ReportConfig     `yaml:"report"`
+	Prices     PricesConfig     `yaml:"prices"`
+	Filter     FilterConfig     `yaml:"filter"`
 	Env        string           `yaml:"env"`
 }
 
@@ -26,25 +34,105 @@ type DatabaseConfig struct {
 }
 
 type ClickHouseConfig struct {
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	User     string `yaml:"user"`
-	Password string `yaml:"password"`
-	DbName   string `yaml:"db_name"`
+	Host           string `yaml:"host"`
+	Port           int    `yaml:"port"`
+	User           string `yaml:"user"`
+	Password       string `yaml:"password"`
+	DbName         string `yaml:"db_name"`
+	WriteBatchSize int    `yaml:"write_batch_size"` // 解析结果写入的批量行数，默认 5000
+	FlushInterval  string `yaml:"flush_interval"`   // 解析结果写入间隔，如 2s
 }
 
 type SolanaConfig struct {
-	RpcUrl string `yaml:"rpc_url"`
+	RpcUrl    string           `yaml:"rpc_url"`
+	ApiKey    string           `yaml:"api_key"`
+	Endpoints []EndpointConfig `yaml:"endpoints"` // getBlock 请求的 RPC 节点池，为空时使用 api_key 对应的 Helius 节点
+}
+
+// EndpointConfig RPC 节点配置
+type EndpointConfig struct {
+	URL       string  `yaml:"url"`
+	Weight    int     `yaml:"weight"`     // 流量权重，默认 1
+	RateLimit float64 `yaml:"rate_limit"` // 每秒请求数上限，0 表示不限制
+	Burst     int     `yaml:"burst"`      // 突发请求数，默认与 rate_limit 相同
 }
 
+// RpcCallConfig Deno 解析服务配置
 type RpcCallConfig struct {
-	Url string `yaml:"url"`
+	Url                 string   `yaml:"url"`
+	Workers             []string `yaml:"workers"`               // 解析服务 worker 地址（可为远程主机），如 http://10.0.0.2:8000
+	PortStart           int      `yaml:"port_start"`            // 未配置 workers 时使用 localhost 上的连续端口，默认 8000
+	PortCount           int      `yaml:"port_count"`            // localhost 端口数，默认 30
+	HealthCheckInterval string   `yaml:"health_check_interval"` // 主动健康检查间隔，如 10s
+}
+
+// ScanConfig scan 子命令默认参数，命令行未指定时使用
+type ScanConfig struct {
+	From     uint64 `yaml:"from"`
+	To       uint64 `yaml:"to"`
+	Cycle    int    `yaml:"cycle"`
+	Batch    int    `yaml:"batch"`
+	Workers  int    `yaml:"workers"`
+	Sink     string `yaml:"sink"`      // deno、clickhouse、file 或 stdout
+	SinkPath string `yaml:"sink_path"` // sink 为 file 时的 NDJSON 输出路径
+	Archive  string `yaml:"archive"`   // 获取的区块同时写入该归档目录
+	Replay   string `yaml:"replay"`    // 从该归档目录回放区块，不发起 RPC 请求
+}
+
+// RetryConfig retry 子命令默认参数
+type RetryConfig struct {
+	Files    []string `yaml:"files"`
+	Attempts int      `yaml:"attempts"`
+}
+
+// FollowConfig follow 子命令默认参数
+type FollowConfig struct {
+	From         uint64 `yaml:"from"`
+	Commitment   string `yaml:"commitment"`
+	PollInterval string `yaml:"poll_interval"` // 如 400ms
+	Batch        int    `yaml:"batch"`
+	ReorgBuffer  int    `yaml:"reorg_buffer"`
+}
+
+// ReportConfig report 子命令默认参数
+type ReportConfig struct {
+	Address string `yaml:"address"`
+}
+
+// PricesConfig prices backfill 子命令默认参数（区块高度范围）
+type PricesConfig struct {
+	From uint64 `yaml:"from"`
+	To   uint64 `yaml:"to"`
+}
+
+// FilterConfig 交易过滤配置，scan / retry / follow 共用
+type FilterConfig struct {
+	Programs        []string `yaml:"programs"`         // DEX_PROGRAMS 中的名称、程序地址或 dex（全部 DEX 程序），为空时使用 Token / Token-2022 程序
+	ExcludeFailed   bool     `yaml:"exclude_failed"`   // 排除执行失败的交易
+	MintAllowlist   []string `yaml:"mint_allowlist"`   // 只保留涉及这些 mint 的交易
+	MintDenylist    []string `yaml:"mint_denylist"`    // 丢弃涉及这些 mint 的交易
+	SignerAllowlist []string `yaml:"signer_allowlist"` // 只保留由这些地址签名的交易
+}
+
+// LoadSvcConfigFile 从指定路径加载配置，失败时返回错误而不退出进程
+func LoadSvcConfigFile(path string) error {
+	cf, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer cf.Close()
+
+	// 空配置文件视为全部使用默认值
+	if err = yaml.NewDecoder(cf).Decode(SvcConfig); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode config file %s: %v", path, err)
+	}
+	return nil
 }
 
 func LoadSvcConfig() error {
 	cf, err := os.Open("./config-yaml/config.yaml")
 	if err != nil {
-		fmt.Println("failed to open config file", err)
+		util.Logln("failed to open config file", err)
 		os.Exit(1)
 	}
 
@@ -53,14 +141,14 @@ func LoadSvcConfig() error {
 		return err
 	}
 
-	fmt.Printf("svcConfig: %+v\n", SvcConfig)
+	util.Logf("svcConfig: %+v\n", SvcConfig)
 	return nil
 }
 
 func LoadSvcConfigFromPath() error {
 	cf, err := os.Open("/Users/a11111/Desktop/code/golang/smartx/solana-dex-parser-golang/config-yaml/config.yaml")
 	if err != nil {
-		fmt.Println("failed to open config file", err)
+		util.Logln("failed to open config file", err)
 		os.Exit(1)
 	}
 
@@ -69,6 +157,6 @@ func LoadSvcConfigFromPath() error {
 		return err
 	}
 
-	fmt.Printf("svcConfig: %+v\n", SvcConfig)
+	util.Logf("svcConfig: %+v\n", SvcConfig)
 	return nil
 }
@@ -8,6 +8,26 @@ const (
 	USDT_ADDRESS = "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"
 )
 
+// 系统程序地址常量
+const (
+	SYSTEM_PROGRAM_ID           = "11111111111111111111111111111111"
+	TOKEN_PROGRAM_ID            = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+	TOKEN_2022_PROGRAM_ID       = "TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb"
+	ASSOCIATED_TOKEN_PROGRAM_ID = "ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL"
+	COMPUTE_BUDGET_PROGRAM_ID   = "ComputeBudget111111111111111111111111111111"
+	MEMO_PROGRAM_ID             = "MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr"
+)
+
+// SYSTEM_PROGRAMS 基础设施程序（不参与 DEX 交易解析）
+var SYSTEM_PROGRAMS = []string{
+	SYSTEM_PROGRAM_ID,
+	TOKEN_PROGRAM_ID,
+	TOKEN_2022_PROGRAM_ID,
+	ASSOCIATED_TOKEN_PROGRAM_ID,
+	COMPUTE_BUDGET_PROGRAM_ID,
+	MEMO_PROGRAM_ID,
+}
+
 // SOLANA_DEX_ADDRESS_TO_NAME 代币地址到名称的映射
 var SOLANA_DEX_ADDRESS_TO_NAME = map[string]string{
 	SOL_ADDRESS:  "SOL",
@@ -65,6 +85,16 @@ func IsBaseToken(tokenAddress string) bool {
 	return false
 }
 
+// IsSystemProgram 检查是否为基础设施程序
+func IsSystemProgram(programID string) bool {
+	for _, id := range SYSTEM_PROGRAMS {
+		if id == programID {
+			return true
+		}
+	}
+	return false
+}
+
 // IsStableToken 检查是否为稳定币
 func IsStableToken(tokenAddress string) bool {
 	for _, stableToken := range SOLANA_DEX_STABLE_TOKEN {
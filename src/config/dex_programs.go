@@ -80,15 +80,15 @@ var DEX_PROGRAMS = map[string]DexProgram{
 
 	// Meteora 系列
 	"METEORA": {
-		ID:   "Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB",
+		ID:   "LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo",
 		Name: "Meteora DLMM",
 	},
 	"METEORA_POOLS": {
-		ID:   "24Uqj9JCLxUeoC3hGfh5W3s9FM9uCHDS2SG3LYwBpyTi",
+		ID:   "Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB",
 		Name: "Meteora Pools",
 	},
 	"METEORA_DAMM": {
-		ID:   "AMM55ShdkoGRB5jVYPjWziwk8m5MpwyDgsMWHaMSQWH6",
+		ID:   "cpamdpZCGKUy5JxQXB4dcpGPiikHawvSWAd6mEn1sGG",
 		Name: "Meteora DAMM",
 	},
 
@@ -98,7 +98,7 @@ var DEX_PROGRAMS = map[string]DexProgram{
 		Name: "Pump.fun",
 	},
 	"PUMP_SWAP": {
-		ID:   "6Ef8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P",
+		ID:   "pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA",
 		Name: "Pumpswap",
 	},
 	"MOONSHOT": {
@@ -0,0 +1,372 @@
+package clickhouse
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	ckdriver "github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// 已写入标识缓存上限，超过后清空，之后依赖写入前的查询去重
+const maxSeenTradeKeys = 1000000
+
+// existingKeysChunkSize 单次查询已写入标识的 tx_hash 数
+const existingKeysChunkSize = 1000
+
+// HistoryDataWriterConfig 异步写入参数
+type HistoryDataWriterConfig struct {
+	BatchSize     int           // 缓冲行数达到该值时写入
+	FlushInterval time.Duration // 缓冲中有数据且距上次写入超过该时间时写入
+	QueueSize     int           // 待写入请求队列长度，队列满时 Write 等待入队
+	MaxRetries    int           // 单批写入失败后的重试次数
+	RetryDelay    time.Duration // 首次重试前的等待时间，之后每次翻倍
+}
+
+// DefaultHistoryDataWriterConfig 默认写入参数
+func DefaultHistoryDataWriterConfig() HistoryDataWriterConfig {
+	return HistoryDataWriterConfig{
+		BatchSize:     5000,
+		FlushInterval: 2 * time.Second,
+		QueueSize:     20000,
+		MaxRetries:    3,
+		RetryDelay:    time.Second,
+	}
+}
+
+// HistoryDataWriterStats 写入统计
+type HistoryDataWriterStats struct {
+	Written    uint64 // 成功写入的行数
+	Duplicates uint64 // 因 (tx_hash, idx) 重复跳过的行数
+	Dropped    uint64 // 重试用尽后丢弃的行数
+	Batches    uint64 // 成功写入的批次数
+}
+
+// HistoryDataWriter 批量写入 solana_history_data_new：多个调用方的行在后台合并，按行数与时间间隔触发写入，失败重试，
+// 按 (tx_hash, idx) 去重，重新扫描同一区块范围不会重复写入
+type HistoryDataWriter struct {
+	config   HistoryDataWriterConfig
+	insert   func(rows []*SolanaHistoryData) error
+	existing func(txHashes []string) (map[TradeKey]bool, error)
+
+	requests chan writeRequest
+	flushReq chan chan error
+	done     chan struct{}
+	closeMu  sync.Mutex
+	closed   bool
+
+	// 以下字段只在写入 goroutine 中访问
+	buffer  []*SolanaHistoryData
+	waiters []chan error
+	seen    map[TradeKey]struct{}
+
+	mu    sync.Mutex
+	stats HistoryDataWriterStats
+}
+
+// writeRequest 一次 Write 的行，写入完成或丢弃后通过 done 返回结果
+type writeRequest struct {
+	rows []*SolanaHistoryData
+	done chan error
+}
+
+// NewHistoryDataWriter 创建写入器并启动后台写入 goroutine，使用完毕后需调用 Close
+func NewHistoryDataWriter(conn ckdriver.Conn, cfg HistoryDataWriterConfig) *HistoryDataWriter {
+	return newHistoryDataWriter(cfg,
+		func(rows []*SolanaHistoryData) error {
+			return SolanaHistoryDataNsp.BatchInsertSolanaHistoryData(conn, rows)
+		},
+		func(txHashes []string) (map[TradeKey]bool, error) {
+			return SolanaHistoryDataNsp.GetExistingTradeKeys(conn, txHashes)
+		},
+	)
+}
+
+func newHistoryDataWriter(cfg HistoryDataWriterConfig, insert func([]*SolanaHistoryData) error, existing func([]string) (map[TradeKey]bool, error)) *HistoryDataWriter {
+	defaults := DefaultHistoryDataWriterConfig()
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaults.FlushInterval
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaults.QueueSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+
+	w := &HistoryDataWriter{
+		config:   cfg,
+		insert:   insert,
+		existing: existing,
+		requests: make(chan writeRequest, cfg.QueueSize),
+		flushReq: make(chan chan error),
+		done:     make(chan struct{}),
+		seen:     make(map[TradeKey]struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write 将行加入待写入队列，等到这些行所在的批次写入完成后返回
+// 返回的错误只属于这些行：重试用尽后这些行被丢弃，调用方据此将对应区块记为失败
+func (w *HistoryDataWriter) Write(rows ...*SolanaHistoryData) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	w.closeMu.Lock()
+	if w.closed {
+		w.closeMu.Unlock()
+		return fmt.Errorf("history data writer is closed")
+	}
+	w.requests <- writeRequest{rows: rows, done: done}
+	w.closeMu.Unlock()
+
+	return <-done
+}
+
+// WriteSwaps 将标准化交易转换为行后写入
+func (w *HistoryDataWriter) WriteSwaps(swaps ...model.SwapTransaction) error {
+	rows := make([]*SolanaHistoryData, 0, len(swaps))
+	for i := range swaps {
+		rows = append(rows, NewSolanaHistoryDataFromSwap(&swaps[i]))
+	}
+	return w.Write(rows...)
+}
+
+// Flush 立即写入队列与缓冲中的全部行，返回这次写入的错误（同时返回给对应的 Write）
+func (w *HistoryDataWriter) Flush() error {
+	w.closeMu.Lock()
+	defer w.closeMu.Unlock()
+	if w.closed {
+		return nil
+	}
+
+	result := make(chan error)
+	w.flushReq <- result
+	return <-result
+}
+
+// Close 写入剩余数据并停止后台 goroutine，写入错误已返回给对应的 Write
+func (w *HistoryDataWriter) Close() error {
+	w.closeMu.Lock()
+	if w.closed {
+		w.closeMu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.requests)
+	w.closeMu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// Stats 返回写入统计
+func (w *HistoryDataWriter) Stats() HistoryDataWriterStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
+
+// run 后台写入循环
+func (w *HistoryDataWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case request, ok := <-w.requests:
+			if !ok {
+				w.flush()
+				return
+			}
+			w.enqueue(request)
+			if len(w.buffer) >= w.config.BatchSize {
+				w.flush()
+			}
+		case <-ticker.C:
+			w.flush()
+		case result := <-w.flushReq:
+			w.drainQueue()
+			result <- w.flush()
+		}
+	}
+}
+
+// enqueue 将请求的行移入缓冲
+func (w *HistoryDataWriter) enqueue(request writeRequest) {
+	w.buffer = append(w.buffer, request.rows...)
+	w.waiters = append(w.waiters, request.done)
+}
+
+// drainQueue 将队列中已有的请求移入缓冲
+func (w *HistoryDataWriter) drainQueue() {
+	for {
+		select {
+		case request, ok := <-w.requests:
+			if !ok {
+				return
+			}
+			w.enqueue(request)
+		default:
+			return
+		}
+	}
+}
+
+// flush 写入缓冲中的行，并将结果返回给这些行的 Write
+func (w *HistoryDataWriter) flush() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	batch, waiters := w.buffer, w.waiters
+	w.buffer, w.waiters = nil, nil
+
+	err := w.writeBatch(batch)
+	for _, done := range waiters {
+		done <- err
+	}
+	return err
+}
+
+// writeBatch 去重后写入一批行，失败时按指数退避重试，重试用尽后丢弃
+func (w *HistoryDataWriter) writeBatch(batch []*SolanaHistoryData) error {
+
+	var err error
+	delay := w.config.RetryDelay
+	for attempt := 0; attempt <= w.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			util.Logf("⏳ ClickHouse 写入失败: %v，%v 后第 %d 次重试\n", err, delay, attempt)
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		var rows []*SolanaHistoryData
+		var duplicates int
+		if rows, duplicates, err = w.dedupe(batch); err != nil {
+			continue
+		}
+		if len(rows) > 0 {
+			if err = w.insert(rows); err != nil {
+				continue
+			}
+		}
+
+		w.remember(rows)
+		w.mu.Lock()
+		w.stats.Written += uint64(len(rows))
+		w.stats.Duplicates += uint64(duplicates)
+		if len(rows) > 0 {
+			w.stats.Batches++
+		}
+		w.mu.Unlock()
+		return nil
+	}
+
+	minBlock, maxBlock := blockHeightRange(batch)
+	err = fmt.Errorf("写入 %d 行失败（区块 %d - %d）: %v", len(batch), minBlock, maxBlock, err)
+	util.Logf("❌ ClickHouse %v\n", err)
+	w.mu.Lock()
+	w.stats.Dropped += uint64(len(batch))
+	w.mu.Unlock()
+	return err
+}
+
+// dedupe 去掉批次内重复、本进程已写入以及表中已存在的行，返回待写入的行与跳过的行数
+func (w *HistoryDataWriter) dedupe(batch []*SolanaHistoryData) ([]*SolanaHistoryData, int, error) {
+	pending := make(map[TradeKey]bool, len(batch))
+	var candidates []*SolanaHistoryData
+	var txHashes []string
+	hashSeen := make(map[string]bool)
+	for _, row := range batch {
+		key := row.Key()
+		if _, ok := w.seen[key]; ok || pending[key] {
+			continue
+		}
+		pending[key] = true
+		candidates = append(candidates, row)
+		if !hashSeen[row.TxHash] {
+			hashSeen[row.TxHash] = true
+			txHashes = append(txHashes, row.TxHash)
+		}
+	}
+
+	existing := make(map[TradeKey]bool)
+	for i := 0; i < len(txHashes); i += existingKeysChunkSize {
+		end := i + existingKeysChunkSize
+		if end > len(txHashes) {
+			end = len(txHashes)
+		}
+		keys, err := w.existing(txHashes[i:end])
+		if err != nil {
+			return nil, 0, err
+		}
+		for key := range keys {
+			existing[key] = true
+		}
+	}
+
+	rows := candidates[:0:0]
+	for _, row := range candidates {
+		if !existing[row.Key()] {
+			rows = append(rows, row)
+		}
+	}
+	return rows, len(batch) - len(rows), nil
+}
+
+// remember 记录已写入的标识
+func (w *HistoryDataWriter) remember(rows []*SolanaHistoryData) {
+	if len(w.seen)+len(rows) > maxSeenTradeKeys {
+		w.seen = make(map[TradeKey]struct{})
+	}
+	for _, row := range rows {
+		w.seen[row.Key()] = struct{}{}
+	}
+}
+
+// blockHeightRange 返回行的最小与最大区块高度
+func blockHeightRange(rows []*SolanaHistoryData) (uint64, uint64) {
+	minBlock, maxBlock := rows[0].BlockHeight, rows[0].BlockHeight
+	for _, row := range rows[1:] {
+		if row.BlockHeight < minBlock {
+			minBlock = row.BlockHeight
+		}
+		if row.BlockHeight > maxBlock {
+			maxBlock = row.BlockHeight
+		}
+	}
+	return minBlock, maxBlock
+}
+
+// NewSolanaHistoryDataFromSwap 将标准化交易转换为行，价格字段无法解析时记为 0
+func NewSolanaHistoryDataFromSwap(swap *model.SwapTransaction) *SolanaHistoryData {
+	row := &SolanaHistoryData{
+		TxHash:          swap.TxHash,
+		TradeType:       swap.TradeType,
+		PoolAddress:     swap.PoolAddress,
+		BlockHeight:     swap.BlockHeight,
+		TransactionTime: swap.TransactionTime,
+		WalletAddress:   swap.WalletAddress,
+		TokenAmount:     swap.TokenAmount,
+		TokenSymbol:     swap.TokenSymbol,
+		TokenAddress:    swap.TokenAddress,
+		QuoteSymbol:     swap.QuoteSymbol,
+		QuoteAmount:     swap.QuoteAmount,
+		QuoteAddress:    swap.QuoteAddress,
+		Idx:             swap.Idx,
+	}
+	row.QuotePrice, _ = parseDecimalToFloat64(swap.QuotePrice)
+	row.UsdPrice, _ = parseDecimalToFloat64(swap.USDPrice)
+	row.UsdAmount, _ = parseDecimalToFloat64(swap.USDAmount)
+	return row
+}
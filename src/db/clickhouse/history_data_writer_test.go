@@ -0,0 +1,137 @@
+package clickhouse
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeHistoryTable 记录写入的行，模拟 tx_hash + idx 查询
+type fakeHistoryTable struct {
+	mu       sync.Mutex
+	rows     map[TradeKey]int
+	batches  [][]*SolanaHistoryData
+	failures int // 剩余失败次数
+}
+
+func newFakeHistoryTable() *fakeHistoryTable {
+	return &fakeHistoryTable{rows: make(map[TradeKey]int)}
+}
+
+func (f *fakeHistoryTable) insert(rows []*SolanaHistoryData) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return fmt.Errorf("connection reset")
+	}
+	for _, row := range rows {
+		f.rows[row.Key()]++
+	}
+	f.batches = append(f.batches, rows)
+	return nil
+}
+
+func (f *fakeHistoryTable) existing(txHashes []string) (map[TradeKey]bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	hashes := make(map[string]bool)
+	for _, hash := range txHashes {
+		hashes[hash] = true
+	}
+	keys := make(map[TradeKey]bool)
+	for key := range f.rows {
+		if hashes[key.TxHash] {
+			keys[key] = true
+		}
+	}
+	return keys, nil
+}
+
+func newTestRow(txHash, idx string) *SolanaHistoryData {
+	return &SolanaHistoryData{TxHash: txHash, Idx: idx, BlockHeight: 100}
+}
+
+func TestHistoryDataWriterFlushesBySizeAndDedupes(t *testing.T) {
+	table := newFakeHistoryTable()
+	table.rows[TradeKey{"tx0", "0-0"}] = 1 // 之前扫描已写入
+
+	w := newHistoryDataWriter(HistoryDataWriterConfig{BatchSize: 4, FlushInterval: 20 * time.Millisecond}, table.insert, table.existing)
+	if err := w.Write(newTestRow("tx0", "0-0"), newTestRow("tx1", "0-0"), newTestRow("tx1", "1-0"), newTestRow("tx1", "0-0")); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	if err := w.Write(newTestRow("tx2", "0-0"), newTestRow("tx1", "1-0")); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	for key, count := range table.rows {
+		if count != 1 {
+			t.Errorf("%v written %d times, want 1", key, count)
+		}
+	}
+	if len(table.rows) != 4 {
+		t.Errorf("rows = %v, want 4 unique keys", table.rows)
+	}
+	if len(table.batches) != 2 || len(table.batches[0]) != 2 {
+		t.Errorf("batches = %d (first %d rows), want size-triggered batch of 2 new rows then remainder", len(table.batches), len(table.batches[0]))
+	}
+	if stats := w.Stats(); stats.Written != 3 || stats.Duplicates != 3 {
+		t.Errorf("stats = %+v, want 3 written and 3 duplicates", stats)
+	}
+}
+
+func TestHistoryDataWriterMergesConcurrentWrites(t *testing.T) {
+	table := newFakeHistoryTable()
+	w := newHistoryDataWriter(HistoryDataWriterConfig{BatchSize: 100, FlushInterval: 20 * time.Millisecond}, table.insert, table.existing)
+	defer w.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := w.Write(newTestRow(fmt.Sprintf("tx%d", i), "0-0")); err != nil {
+				t.Errorf("Write() = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Write 返回时其行已写入
+	if stats := w.Stats(); stats.Written != 4 {
+		t.Errorf("stats = %+v, want 4 written", stats)
+	}
+}
+
+func TestHistoryDataWriterReturnsErrorToFailedRows(t *testing.T) {
+	table := newFakeHistoryTable()
+	table.failures = 2
+	w := newHistoryDataWriter(HistoryDataWriterConfig{BatchSize: 1, FlushInterval: time.Hour, MaxRetries: 2, RetryDelay: time.Millisecond}, table.insert, table.existing)
+
+	if err := w.Write(newTestRow("tx1", "0-0")); err != nil {
+		t.Fatalf("Write() = %v, want success after retries", err)
+	}
+
+	table.failures = 3
+	if err := w.Write(newTestRow("tx2", "0-0")); err == nil {
+		t.Fatal("Write() = nil, want error after retries exhausted")
+	}
+	// 之前批次的失败不会记到之后的行上
+	if err := w.Write(newTestRow("tx3", "0-0")); err != nil {
+		t.Errorf("Write() after dropped batch = %v, want nil", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Close() = %v, want nil", err)
+	}
+
+	if stats := w.Stats(); stats.Written != 2 || stats.Dropped != 1 {
+		t.Errorf("stats = %+v, want 2 written and 1 dropped", stats)
+	}
+	if table.rows[TradeKey{"tx2", "0-0"}] != 0 {
+		t.Errorf("dropped row was written")
+	}
+}
@@ -25,6 +25,18 @@ type SolanaHistoryData struct {
 	QuotePrice      float64 `json:"quote_price" gorm:"column:quote_price"`
 	UsdPrice        float64 `json:"usd_price" gorm:"column:usd_price"`
 	UsdAmount       float64 `json:"usd_amount" gorm:"column:usd_amount"`
+	Idx             string  `json:"idx" gorm:"column:idx"` // 交易在所属交易中的指令位置，与 tx_hash 共同唯一标识一笔 swap
+}
+
+// TradeKey 一笔 swap 的唯一标识
+type TradeKey struct {
+	TxHash string
+	Idx    string
+}
+
+// Key 返回行的唯一标识
+func (s *SolanaHistoryData) Key() TradeKey {
+	return TradeKey{TxHash: s.TxHash, Idx: s.Idx}
 }
 
 var SolanaHistoryDataNsp = &SolanaHistoryData{}
@@ -303,6 +315,61 @@ func (s *SolanaHistoryData) GetUserFirstTransaction(db ckdriver.Conn, address st
 	return tx, nil
 }
 
+// BatchInsertSolanaHistoryData 批量插入交易数据
+func (s *SolanaHistoryData) BatchInsertSolanaHistoryData(db ckdriver.Conn, rows []*SolanaHistoryData) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	batch, err := db.PrepareBatch(context.Background(), `
+		INSERT INTO `+s.TableName()+` (tx_hash, trade_type, pool_address, block_height, transaction_time,
+			wallet_address, token_amount, token_symbol, token_address,
+			quote_symbol, quote_amount, quote_address, quote_price, usd_price, usd_amount, idx)`)
+	if err != nil {
+		return fmt.Errorf("准备批量插入失败: %v", err)
+	}
+
+	for _, row := range rows {
+		err := batch.Append(
+			row.TxHash, row.TradeType, row.PoolAddress, row.BlockHeight, row.TransactionTime,
+			row.WalletAddress, row.TokenAmount, row.TokenSymbol, row.TokenAddress,
+			row.QuoteSymbol, row.QuoteAmount, row.QuoteAddress, row.QuotePrice, row.UsdPrice, row.UsdAmount, row.Idx,
+		)
+		if err != nil {
+			return fmt.Errorf("添加批量数据失败: %v", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("发送批量数据失败: %v", err)
+	}
+	return nil
+}
+
+// GetExistingTradeKeys 查询 txHashes 中已写入的 swap 标识
+func (s *SolanaHistoryData) GetExistingTradeKeys(db ckdriver.Conn, txHashes []string) (map[TradeKey]bool, error) {
+	existing := make(map[TradeKey]bool)
+	if len(txHashes) == 0 {
+		return existing, nil
+	}
+
+	query := "SELECT tx_hash, idx FROM " + s.TableName() + " WHERE tx_hash IN (?)"
+	rows, err := db.Query(context.Background(), query, txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("查询已写入交易失败: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key TradeKey
+		if err := rows.Scan(&key.TxHash, &key.Idx); err != nil {
+			return nil, err
+		}
+		existing[key] = true
+	}
+	return existing, rows.Err()
+}
+
 // 通过查找在该区块高度之前的最后一笔交易来推导价格
 func (s *SolanaHistoryData) GetTokenPriceAtBlock(db ckdriver.Conn, tokenAddress string, blockHeight uint64) (*SolanaHistoryData, error) {
 	query := `
@@ -5,6 +5,7 @@ import (
 
 	ckdriver "github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/util"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -19,7 +20,7 @@ func InitDB() error {
 		config.SvcConfig.DB.Host,
 		config.SvcConfig.DB.Port,
 		config.SvcConfig.DB.DbName)
-	fmt.Printf("MySQL connection string: %s\n", dbUrl)
+	util.Logf("MySQL connection string: %s\n", dbUrl)
 	db, err := gorm.Open(mysql.Open(dbUrl), &gorm.Config{
 		// 使用默认logger替代可能有问题的自定义logger
 	})
@@ -39,14 +40,14 @@ func InitDB() error {
 		return fmt.Errorf("failed to ping MySQL: %v", err)
 	}
 
-	fmt.Println("✅ MySQL connection and ping successful!")
+	util.Logln("✅ MySQL connection and ping successful!")
 	return nil
 }
 
 func InitClickHouseV2() error {
 	conn, err := Connect()
 
-	fmt.Println("conn", conn)
+	util.Logln("conn", conn)
 	if err != nil {
 		return err
 	}
@@ -57,7 +57,10 @@ type DexInfo struct {
 }
 
 // TransferData 转账数据
+// Index 为外层指令索引，InstructionIndex 为内层指令索引（外层指令为 -1）
 type TransferData struct {
+	Type             string       `json:"type"`
+	ProgramID        string       `json:"program_id"`
 	Index            int          `json:"index"`
 	InstructionIndex int          `json:"instruction_index"`
 	Info             TransferInfo `json:"info"`
@@ -65,40 +68,64 @@ type TransferData struct {
 
 // TransferInfo 转账信息
 type TransferInfo struct {
-	Source      string `json:"source"`
-	Destination string `json:"destination"`
-	Owner       string `json:"owner"`
-	Mint        string `json:"mint"`
-	Amount      string `json:"amount"`
-	Decimals    uint8  `json:"decimals"`
+	Source           string `json:"source"`
+	Destination      string `json:"destination"`
+	Owner            string `json:"owner"`
+	SourceOwner      string `json:"source_owner"`
+	DestinationOwner string `json:"destination_owner"`
+	Mint             string `json:"mint"`
+	Amount           string `json:"amount"`
+	Fee              string `json:"fee,omitempty"`
+	Decimals         uint8  `json:"decimals"`
 }
 
 // TradeInfo 交易信息
+// TokenInAmount / TokenOutAmount 为链上原始整数数量，配合 Decimals 换算为 UI 数量
 type TradeInfo struct {
-	Signature      string    `json:"signature"`
-	Type           TradeType `json:"type"`
-	Signer         string    `json:"signer"`
-	TokenInMint    string    `json:"token_in_mint"`
-	TokenInSymbol  string    `json:"token_in_symbol"`
-	TokenInAmount  string    `json:"token_in_amount"`
-	TokenOutMint   string    `json:"token_out_mint"`
-	TokenOutSymbol string    `json:"token_out_symbol"`
-	TokenOutAmount string    `json:"token_out_amount"`
-	SlotNumber     uint64    `json:"slot_number"`
-	BlockTime      uint64    `json:"block_time"`
-	AMM            string    `json:"amm"`
-	AMMs           []string  `json:"amms"`
-	Route          string    `json:"route"`
-	IDX            string    `json:"idx"`
+	Signature        string      `json:"signature"`
+	Type             TradeType   `json:"type"`
+	Signer           string      `json:"signer"`
+	TokenInMint      string      `json:"token_in_mint"`
+	TokenInSymbol    string      `json:"token_in_symbol"`
+	TokenInAmount    string      `json:"token_in_amount"`
+	TokenInDecimals  uint8       `json:"token_in_decimals"`
+	TokenOutMint     string      `json:"token_out_mint"`
+	TokenOutSymbol   string      `json:"token_out_symbol"`
+	TokenOutAmount   string      `json:"token_out_amount"`
+	TokenOutDecimals uint8       `json:"token_out_decimals"`
+	SlotNumber       uint64      `json:"slot_number"`
+	BlockTime        uint64      `json:"block_time"`
+	ProgramID        string      `json:"program_id"`
+	PoolAddress      string      `json:"pool_address"`
+	AMM              string      `json:"amm"`
+	AMMs             []string    `json:"amms"`
+	Route            string      `json:"route"`
+	IDX              string      `json:"idx"`
+	Hops             []TradeInfo `json:"hops,omitempty"` // 聚合路由中各跳底层 AMM 的交易，不计入顶层交易
 }
 
-// PoolEvent 流动性池事件
+// 流动性池事件类型
+const (
+	PoolEventTypeCreate = "CREATE"
+	PoolEventTypeAdd    = "ADD"
+	PoolEventTypeRemove = "REMOVE"
+)
+
+// PoolEvent 流动性池事件，代币数量为未按精度换算的原始整数字符串
 type PoolEvent struct {
-	Signature   string `json:"signature"`
-	Type        string `json:"type"`
-	Signer      string `json:"signer"`
-	PoolAddress string `json:"pool_address"`
-	SlotNumber  uint64 `json:"slot_number"`
-	BlockTime   uint64 `json:"block_time"`
-	AMM         string `json:"amm"`
+	Signature      string `json:"signature"`
+	Type           string `json:"type"`
+	Signer         string `json:"signer"`
+	PoolAddress    string `json:"pool_address"`
+	SlotNumber     uint64 `json:"slot_number"`
+	BlockTime      uint64 `json:"block_time"`
+	ProgramID      string `json:"program_id"`
+	AMM            string `json:"amm"`
+	Token0Mint     string `json:"token0_mint"`
+	Token0Amount   string `json:"token0_amount"`
+	Token0Decimals uint8  `json:"token0_decimals"`
+	Token1Mint     string `json:"token1_mint"`
+	Token1Amount   string `json:"token1_amount"`
+	Token1Decimals uint8  `json:"token1_decimals"`
+	IDX            string `json:"idx"`
 }
@@ -46,13 +46,17 @@ type ResSwapStruct struct {
 
 // ResLpInfoStruct 流动性池信息结构
 type ResLpInfoStruct struct {
-	Signature   string `json:"signature"`
-	Type        string `json:"type"`
-	Signer      string `json:"signer"`
-	PoolAddress string `json:"pool_address"`
-	SlotNumber  uint64 `json:"slot_number"`
-	BlockTime   uint64 `json:"block_time"`
-	AMM         string `json:"amm"`
+	Signature    string  `json:"signature"`
+	Type         string  `json:"type"`
+	Signer       string  `json:"signer"`
+	PoolAddress  string  `json:"pool_address"`
+	SlotNumber   uint64  `json:"slot_number"`
+	BlockTime    uint64  `json:"block_time"`
+	AMM          string  `json:"amm"`
+	Token0Mint   string  `json:"token0_mint"`
+	Token0Amount float64 `json:"token0_amount"`
+	Token1Mint   string  `json:"token1_mint"`
+	Token1Amount float64 `json:"token1_amount"`
 }
 
 // ResTokenMetadataStruct 代币元数据结构
@@ -61,6 +65,7 @@ type ResTokenMetadataStruct struct {
 	Symbol   string `json:"symbol"`
 	Name     string `json:"name"`
 	Decimals uint8  `json:"decimals"`
+	URI      string `json:"uri"`
 }
 
 // ResTokenPriceStruct 代币价格结构
@@ -97,6 +102,7 @@ type SwapTransaction struct {
 	TradeType       string  `json:"trade_type"`
 	PoolAddress     string  `json:"pool_address"`
 	BlockHeight     uint64  `json:"block_height"`
+	Idx             string  `json:"idx"` // 对应 TradeInfo.IDX，与 TxHash 共同唯一标识一笔 swap
 }
 
 // VersionedBlockResponse 版本化区块响应（与现有 solana.Block 兼容）
@@ -0,0 +1,28 @@
+package model
+
+// PumpfunTradeEvent Pump.fun 联合曲线交易事件
+type PumpfunTradeEvent struct {
+	Mint                 string `json:"mint"`
+	SolAmount            uint64 `json:"sol_amount"`
+	TokenAmount          uint64 `json:"token_amount"`
+	IsBuy                bool   `json:"is_buy"`
+	User                 string `json:"user"`
+	Timestamp            int64  `json:"timestamp"`
+	VirtualSolReserves   uint64 `json:"virtual_sol_reserves"`
+	VirtualTokenReserves uint64 `json:"virtual_token_reserves"`
+	RealSolReserves      uint64 `json:"real_sol_reserves"`   // 旧版本事件不包含，为 0
+	RealTokenReserves    uint64 `json:"real_token_reserves"` // 旧版本事件不包含，为 0
+	BondingCurve         string `json:"bonding_curve"`
+	IDX                  string `json:"idx"`
+}
+
+// PumpfunCreateEvent Pump.fun 新代币创建事件
+type PumpfunCreateEvent struct {
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	URI          string `json:"uri"`
+	Mint         string `json:"mint"`
+	BondingCurve string `json:"bonding_curve"`
+	User         string `json:"user"`
+	IDX          string `json:"idx"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "fmt"
+
 // RPC request structure for Solana JSON-RPC calls
 type RPCRequest struct {
 	JSONRPC string        `json:"jsonrpc"`
@@ -29,6 +31,23 @@ type RPCError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// getBlock 返回的区块不存在类错误码
+const (
+	RPCErrorBlockNotAvailable      = -32004 // 区块尚未产出或尚未达到请求的确认级别
+	RPCErrorSlotSkipped            = -32007 // slot 被 leader 跳过，或因快照跳跃缺失
+	RPCErrorLongTermStorageSkipped = -32009 // slot 被跳过，或该节点的长期存储中缺失（其他归档节点可能有该区块）
+)
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error: %s (code: %d)", e.Message, e.Code)
+}
+
+// IsSlotSkipped 是否为 slot 被跳过（重试也无法获取）的永久性错误
+// -32009 取决于节点的存储范围，不视为永久性错误
+func (e *RPCError) IsSlotSkipped() bool {
+	return e.Code == RPCErrorSlotSkipped
+}
+
 // Transaction structure
 type TransactionInfo struct {
 	Transaction struct {
@@ -56,6 +75,7 @@ type TransactionInstruction struct {
 	ProgramIdIndex int    `json:"programIdIndex"`
 	Accounts       []int  `json:"accounts"`
 	Data           string `json:"data"`
+	StackHeight    *int   `json:"stackHeight,omitempty"`
 }
 
 type AddressTableLookup struct {
@@ -125,7 +145,7 @@ type Block struct {
 
 // BlockResult represents the result of a block fetch operation
 type BlockResult struct {
-	Block Block
+	Block *Block
 	Slot  uint64
 	Error error
 }
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// anchorEventInstructionTag Anchor emit_cpi! 自调用指令的数据前缀
+var anchorEventInstructionTag = []byte{0xe4, 0x45, 0xa5, 0x2e, 0x51, 0xcb, 0x9a, 0x1d}
+
+// AnchorEvent Anchor 程序发出的事件
+type AnchorEvent struct {
+	Data        []byte                       // 事件标识（8 字节）+ 事件数据
+	Instruction *model.ClassifiedInstruction // 来自自调用指令时为该指令，来自日志时为 nil
+}
+
+// Is 判断事件是否为指定事件标识
+func (e AnchorEvent) Is(discriminator []byte) bool {
+	return bytes.HasPrefix(e.Data, discriminator)
+}
+
+// Payload 去掉事件标识后的事件数据
+func (e AnchorEvent) Payload() []byte {
+	if len(e.Data) < 8 {
+		return nil
+	}
+	return e.Data[8:]
+}
+
+// GetAnchorEvents 获取程序按执行顺序发出的事件
+// 优先读取自调用（self-CPI）内层指令，交易中没有时回退到 "Program data:" 日志
+func GetAnchorEvents(adapter *TransactionAdapter, classifier *InstructionClassifier, programID string) []AnchorEvent {
+	events := []AnchorEvent{}
+	for _, instruction := range classifier.GetInstructions(programID) {
+		if instruction.InnerIndex == nil {
+			continue
+		}
+		data, err := util.Base58Decode(GetInstruction(instruction).Data)
+		if err != nil || !bytes.HasPrefix(data, anchorEventInstructionTag) {
+			continue
+		}
+		classified := instruction
+		events = append(events, AnchorEvent{Data: data[len(anchorEventInstructionTag):], Instruction: &classified})
+	}
+	if len(events) > 0 {
+		return events
+	}
+
+	if meta := adapter.Meta(); meta != nil {
+		for _, data := range getProgramDataLogs(meta.LogMessages, programID) {
+			events = append(events, AnchorEvent{Data: data})
+		}
+	}
+	return events
+}
+
+// getProgramDataLogs 按调用栈提取指定程序输出的 "Program data:" 日志并做 base64 解码
+func getProgramDataLogs(logs []string, programID string) [][]byte {
+	var result [][]byte
+	var stack []string
+	for _, log := range logs {
+		if encoded, ok := strings.CutPrefix(log, "Program data: "); ok {
+			if len(stack) == 0 || stack[len(stack)-1] != programID {
+				continue
+			}
+			if data, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+				result = append(result, data)
+			}
+			continue
+		}
+
+		parts := strings.Fields(log)
+		if len(parts) < 3 || parts[0] != "Program" {
+			continue
+		}
+		switch {
+		case parts[2] == "invoke":
+			stack = append(stack, parts[1])
+		case parts[2] == "success" || parts[2] == "failed:":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"math/big"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+// BalanceChanges 某个账户所有者在一笔交易中的余额变化
+// SOL 为钱包账户 lamports 的原始变化（包含手续费），Fee 为该账户承担的手续费
+type BalanceChanges struct {
+	Owner  string
+	SOL    *model.BalanceChange
+	Fee    *big.Int
+	Tokens map[string]*model.BalanceChange // mint -> 该所有者全部代币账户的汇总变化
+}
+
+// SOLChangeWithoutFee 扣除手续费影响后的 SOL 变化
+func (c *BalanceChanges) SOLChangeWithoutFee() *big.Int {
+	if c.SOL == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Add(c.SOL.Change, c.Fee)
+}
+
+// GetBalanceChanges 计算交易中指定所有者的 SOL 与代币余额变化
+func GetBalanceChanges(tx *model.TransactionInfo, owner string) *BalanceChanges {
+	return CalculateBalanceChanges(NewTransactionAdapter(tx, 0, 0), owner)
+}
+
+// CalculateBalanceChanges 对比交易前后余额，计算指定所有者的精确变化
+func CalculateBalanceChanges(adapter *TransactionAdapter, owner string) *BalanceChanges {
+	changes := &BalanceChanges{
+		Owner:  owner,
+		Fee:    big.NewInt(0),
+		Tokens: make(map[string]*model.BalanceChange),
+	}
+
+	meta := adapter.Meta()
+	if meta == nil {
+		return changes
+	}
+
+	// 手续费只由第一个签名者（手续费支付者）承担
+	if owner == adapter.Signer() {
+		changes.Fee.SetUint64(meta.Fee)
+	}
+
+	if index := adapter.GetAccountIndex(owner); index >= 0 && index < len(meta.PreBalances) && index < len(meta.PostBalances) {
+		before := new(big.Int).SetUint64(meta.PreBalances[index])
+		after := new(big.Int).SetUint64(meta.PostBalances[index])
+		changes.SOL = &model.BalanceChange{
+			Before: before,
+			After:  after,
+			Change: new(big.Int).Sub(after, before),
+		}
+	}
+
+	get := func(mint string) *model.BalanceChange {
+		change, ok := changes.Tokens[mint]
+		if !ok {
+			change = &model.BalanceChange{Before: big.NewInt(0), After: big.NewInt(0), Change: big.NewInt(0)}
+			changes.Tokens[mint] = change
+		}
+		return change
+	}
+
+	// 交易中新建的账户只出现在 post，被关闭的账户只出现在 pre，缺失一侧按 0 计算
+	for _, balance := range meta.PreTokenBalances {
+		if balance.Owner != owner {
+			continue
+		}
+		change := get(balance.Mint)
+		change.Before.Add(change.Before, parseRawAmount(balance.UiTokenAmount.Amount))
+	}
+	for _, balance := range meta.PostTokenBalances {
+		if balance.Owner != owner {
+			continue
+		}
+		change := get(balance.Mint)
+		change.After.Add(change.After, parseRawAmount(balance.UiTokenAmount.Amount))
+	}
+
+	for mint, change := range changes.Tokens {
+		change.Change.Sub(change.After, change.Before)
+		if change.Change.Sign() == 0 {
+			delete(changes.Tokens, mint)
+		}
+	}
+
+	return changes
+}
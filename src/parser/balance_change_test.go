@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+func TestCalculateBalanceChanges(t *testing.T) {
+	const (
+		otherOwner = "poolAuthority111111111111111111111111111111"
+		account2   = "userTokenAccount2222222222222222222222222222"
+	)
+
+	tx := newTestTransaction()
+	tx.Transaction.Message.AccountKeys = append(tx.Transaction.Message.AccountKeys, account2)
+	tx.Meta.PreBalances = append(tx.Meta.PreBalances, 2039280)
+	tx.Meta.PostBalances = append(tx.Meta.PostBalances, 0)
+	// 第二个代币账户在交易中被关闭，只出现在 pre 中
+	tx.Meta.PreTokenBalances = append(tx.Meta.PreTokenBalances,
+		model.TokenBalance{AccountIndex: len(tx.Transaction.Message.AccountKeys) - 1, Mint: testMint, Owner: testSigner, UiTokenAmount: model.UiTokenAmount{Amount: "250", Decimals: 6}},
+		model.TokenBalance{AccountIndex: 1, Mint: "otherMint", Owner: otherOwner, UiTokenAmount: model.UiTokenAmount{Amount: "1", Decimals: 6}},
+	)
+
+	changes := GetBalanceChanges(tx, testSigner)
+	if changes.Fee.Int64() != 5000 {
+		t.Fatalf("unexpected fee: %s", changes.Fee)
+	}
+	if changes.SOL.Change.Int64() != -1_000_005_000 {
+		t.Fatalf("unexpected raw SOL change: %s", changes.SOL.Change)
+	}
+	if changes.SOLChangeWithoutFee().Int64() != -1_000_000_000 {
+		t.Fatalf("unexpected SOL change without fee: %s", changes.SOLChangeWithoutFee())
+	}
+
+	token, ok := changes.Tokens[testMint]
+	if !ok || token.Before.Int64() != 250 || token.After.Int64() != 1000000000 || token.Change.Int64() != 999999750 {
+		t.Fatalf("unexpected token change: %+v", token)
+	}
+	if _, ok := changes.Tokens["otherMint"]; ok {
+		t.Fatalf("balances of other owners must be ignored")
+	}
+
+	other := GetBalanceChanges(tx, otherOwner)
+	if other.Fee.Sign() != 0 || other.SOL != nil {
+		t.Fatalf("non fee payer should have no fee and no wallet account: %+v", other)
+	}
+	if change := other.Tokens["otherMint"]; change == nil || change.Change.Int64() != -1 {
+		t.Fatalf("unexpected change for other owner: %+v", change)
+	}
+}
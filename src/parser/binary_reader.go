@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/go-solana-parse/src/util"
+)
+
+// BinaryReader 小端序（borsh）指令数据读取器
+type BinaryReader struct {
+	data   []byte
+	offset int
+}
+
+// NewBinaryReader 创建读取器
+func NewBinaryReader(data []byte) *BinaryReader {
+	return &BinaryReader{data: data}
+}
+
+// Remaining 剩余未读取字节数
+func (r *BinaryReader) Remaining() int {
+	return len(r.data) - r.offset
+}
+
+// Skip 跳过指定字节数
+func (r *BinaryReader) Skip(n int) error {
+	if err := r.check(n); err != nil {
+		return err
+	}
+	r.offset += n
+	return nil
+}
+
+// ReadU8 读取 u8
+func (r *BinaryReader) ReadU8() (uint8, error) {
+	if err := r.check(1); err != nil {
+		return 0, err
+	}
+	value := r.data[r.offset]
+	r.offset++
+	return value, nil
+}
+
+// ReadBool 读取 bool
+func (r *BinaryReader) ReadBool() (bool, error) {
+	value, err := r.ReadU8()
+	return value != 0, err
+}
+
+// ReadU32 读取 u32
+func (r *BinaryReader) ReadU32() (uint32, error) {
+	if err := r.check(4); err != nil {
+		return 0, err
+	}
+	value := binary.LittleEndian.Uint32(r.data[r.offset:])
+	r.offset += 4
+	return value, nil
+}
+
+// ReadU64 读取 u64
+func (r *BinaryReader) ReadU64() (uint64, error) {
+	if err := r.check(8); err != nil {
+		return 0, err
+	}
+	value := binary.LittleEndian.Uint64(r.data[r.offset:])
+	r.offset += 8
+	return value, nil
+}
+
+// ReadI64 读取 i64
+func (r *BinaryReader) ReadI64() (int64, error) {
+	value, err := r.ReadU64()
+	return int64(value), err
+}
+
+// ReadPubkey 读取 32 字节公钥并编码为 base58
+func (r *BinaryReader) ReadPubkey() (string, error) {
+	if err := r.check(32); err != nil {
+		return "", err
+	}
+	value := util.Base58Encode(r.data[r.offset : r.offset+32])
+	r.offset += 32
+	return value, nil
+}
+
+// ReadString 读取 borsh 字符串（u32 长度前缀）
+func (r *BinaryReader) ReadString() (string, error) {
+	length, err := r.ReadU32()
+	if err != nil {
+		return "", err
+	}
+	if err := r.check(int(length)); err != nil {
+		return "", err
+	}
+	value := string(r.data[r.offset : r.offset+int(length)])
+	r.offset += int(length)
+	return value, nil
+}
+
+func (r *BinaryReader) check(n int) error {
+	if n < 0 || r.offset+n > len(r.data) {
+		return fmt.Errorf("binary reader: need %d bytes at offset %d, have %d", n, r.offset, len(r.data))
+	}
+	return nil
+}
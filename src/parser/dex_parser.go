@@ -0,0 +1,230 @@
+package parser
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// DexParser 单个 DEX 协议的交易解析器
+type DexParser interface {
+	// ProcessTrades 解析交易中该协议产生的全部交易
+	ProcessTrades(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.TradeInfo
+}
+
+// EventParser 可选接口：解析协议自定义事件，结果按事件名合并到 ParseResult.MoreEvents
+type EventParser interface {
+	ProcessEvents(adapter *TransactionAdapter, classifier *InstructionClassifier) map[string]interface{}
+}
+
+// TokenParser 可选接口：解析新代币创建，结果写入 EnhancedResult.Tokens
+type TokenParser interface {
+	ProcessTokens(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.ResTokenMetadataStruct
+}
+
+// dexParsers programID -> 协议解析器，在各协议文件的 init 中注册
+var dexParsers = map[string]DexParser{}
+
+// RegisterDexParser 注册协议解析器，同一程序 ID 重复注册时覆盖
+func RegisterDexParser(programID string, parser DexParser) {
+	dexParsers[programID] = parser
+}
+
+// GetDexParser 获取程序对应的专用解析器
+// 没有专用解析器的程序（包括 DEX_PROGRAMS 中的交易机器人与路由）只在 TryUnknownDEX 开启时使用通用转账推断
+func GetDexParser(programID string) (DexParser, bool) {
+	parser, ok := dexParsers[programID]
+	return parser, ok
+}
+
+// isDecodedTrade 交易是否由专用解析器解码，否则为转账推断得到
+func isDecodedTrade(trade model.TradeInfo) bool {
+	_, ok := dexParsers[trade.ProgramID]
+	return ok
+}
+
+// TransferDexParser 通用解析器：根据指令内层转账推断用户交易
+type TransferDexParser struct {
+	programID string
+}
+
+// NewTransferDexParser 创建通用转账推断解析器
+func NewTransferDexParser(programID string) *TransferDexParser {
+	return &TransferDexParser{programID: programID}
+}
+
+// ProcessTrades 对该程序的每条指令配对内层转账
+func (p *TransferDexParser) ProcessTrades(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.TradeInfo {
+	trades := []model.TradeInfo{}
+	for _, instruction := range classifier.GetInstructions(p.programID) {
+		transfers := GetTransfersOf(adapter, classifier, instruction)
+		if trade := BuildTradeFromTransfers(adapter, instruction, transfers, adapter.Signer()); trade != nil {
+			trades = append(trades, *trade)
+		}
+	}
+	return trades
+}
+
+// BuildTradeFromTransfers 将指令内层转账配对为交易
+// 优先取用户第一笔转出与最后一笔转入（多跳时跳过中间代币）；无法识别时取第一笔转账为卖出、最后一笔不同代币的转账为买入
+func BuildTradeFromTransfers(adapter *TransactionAdapter, instruction model.ClassifiedInstruction, transfers []model.TransferData, user string) *model.TradeInfo {
+	swapTransfers := make([]model.TransferData, 0, len(transfers))
+	for _, transfer := range transfers {
+		if isSwapTransfer(transfer) {
+			swapTransfers = append(swapTransfers, transfer)
+		}
+	}
+	if len(swapTransfers) < 2 {
+		return nil
+	}
+
+	var in, out *model.TransferData
+	for i := range swapTransfers {
+		transfer := &swapTransfers[i]
+		if in == nil && transfer.Info.SourceOwner == user {
+			in = transfer
+		} else if transfer.Info.DestinationOwner == user {
+			out = transfer
+		}
+	}
+
+	if in == nil || out == nil || normalizeMint(in.Info.Mint) == normalizeMint(out.Info.Mint) {
+		in, out = &swapTransfers[0], nil
+		for i := len(swapTransfers) - 1; i > 0; i-- {
+			if normalizeMint(swapTransfers[i].Info.Mint) != normalizeMint(in.Info.Mint) {
+				out = &swapTransfers[i]
+				break
+			}
+		}
+		if out == nil {
+			return nil
+		}
+	}
+
+	return NewTradeInfo(adapter, instruction, normalizeMint(in.Info.Mint), parseRawAmount(in.Info.Amount), normalizeMint(out.Info.Mint), parseRawAmount(out.Info.Amount))
+}
+
+// NewTradeInfo 按输入/输出代币构造交易，买卖方向由输入代币是否为基础代币决定
+func NewTradeInfo(adapter *TransactionAdapter, instruction model.ClassifiedInstruction, inMint string, inAmount *big.Int, outMint string, outAmount *big.Int) *model.TradeInfo {
+	if inAmount.Sign() <= 0 || outAmount.Sign() <= 0 {
+		return nil
+	}
+
+	tradeType := model.TradeTypeSell
+	if config.IsBaseToken(inMint) {
+		tradeType = model.TradeTypeBuy
+	}
+
+	amm := getAMMName(instruction.ProgramID)
+
+	return &model.TradeInfo{
+		Signature:        adapter.Signature(),
+		Type:             tradeType,
+		Signer:           adapter.Signer(),
+		TokenInMint:      inMint,
+		TokenInSymbol:    getTokenSymbol(inMint),
+		TokenInAmount:    inAmount.String(),
+		TokenInDecimals:  adapter.TokenDecimals(inMint),
+		TokenOutMint:     outMint,
+		TokenOutSymbol:   getTokenSymbol(outMint),
+		TokenOutAmount:   outAmount.String(),
+		TokenOutDecimals: adapter.TokenDecimals(outMint),
+		SlotNumber:       adapter.Slot(),
+		BlockTime:        adapter.BlockTime(),
+		ProgramID:        instruction.ProgramID,
+		AMM:              amm,
+		AMMs:             []string{amm},
+		IDX:              GetIDX(instruction),
+	}
+}
+
+// getAMMName 获取程序名称，未登记的程序使用程序 ID
+func getAMMName(programID string) string {
+	if _, ok := config.GetDexProgramByID(programID); ok {
+		return config.GetProgramName(programID)
+	}
+	return programID
+}
+
+// isSwapTransfer 判断是否为参与兑换的转账（排除铸造、销毁与关闭账户）
+func isSwapTransfer(transfer model.TransferData) bool {
+	switch transfer.Type {
+	case TRANSFER_TYPE_TRANSFER, TRANSFER_TYPE_TRANSFER_CHECKED, TRANSFER_TYPE_TRANSFER_CHECKED_WITH_FEE:
+		return transfer.Info.Mint != ""
+	}
+	return false
+}
+
+// normalizeMint 原生 SOL 统一记为 WSOL
+func normalizeMint(mint string) string {
+	if mint == config.SOL_ADDRESS {
+		return config.WSOL_ADDRESS
+	}
+	return mint
+}
+
+// swapInstruction 兑换指令标识及其池地址所在账户位置
+type swapInstruction struct {
+	discriminator []byte
+	poolIndex     int
+}
+
+// SwapInstructionParser 按指令标识识别兑换指令，从固定账户位置读取池地址并配对内层转账
+type SwapInstructionParser struct {
+	programID string
+	swaps     []swapInstruction
+}
+
+// NewSwapInstructionParser 创建按指令标识匹配的兑换解析器
+func NewSwapInstructionParser(programID string, poolIndex int, discriminators ...[]byte) *SwapInstructionParser {
+	return (&SwapInstructionParser{programID: programID}).WithSwap(poolIndex, discriminators...)
+}
+
+// WithSwap 追加池地址位于其他账户位置的兑换指令
+func (p *SwapInstructionParser) WithSwap(poolIndex int, discriminators ...[]byte) *SwapInstructionParser {
+	for _, discriminator := range discriminators {
+		p.swaps = append(p.swaps, swapInstruction{discriminator: discriminator, poolIndex: poolIndex})
+	}
+	return p
+}
+
+// ProcessTrades 解析该程序全部兑换指令
+func (p *SwapInstructionParser) ProcessTrades(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.TradeInfo {
+	trades := []model.TradeInfo{}
+	for _, instruction := range classifier.GetInstructions(p.programID) {
+		poolIndex, ok := p.matchSwap(instruction)
+		if !ok {
+			continue
+		}
+		accounts := classifier.GetInstructionAccounts(instruction)
+		if len(accounts) <= poolIndex {
+			continue
+		}
+
+		transfers := GetTransfersOf(adapter, classifier, instruction)
+		trade := BuildTradeFromTransfers(adapter, instruction, transfers, adapter.Signer())
+		if trade == nil {
+			continue
+		}
+		trade.PoolAddress = accounts[poolIndex]
+		trades = append(trades, *trade)
+	}
+	return trades
+}
+
+// matchSwap 匹配兑换指令，返回池地址所在账户位置
+func (p *SwapInstructionParser) matchSwap(instruction model.ClassifiedInstruction) (int, bool) {
+	data, err := util.Base58Decode(GetInstruction(instruction).Data)
+	if err != nil {
+		return 0, false
+	}
+	for _, swap := range p.swaps {
+		if bytes.HasPrefix(data, swap.discriminator) {
+			return swap.poolIndex, true
+		}
+	}
+	return 0, false
+}
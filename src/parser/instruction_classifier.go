@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"github.com/go-solana-parse/src/model"
+)
+
+// InstructionClassifier 指令分类器：展开外层与内层指令并按程序 ID 分组
+type InstructionClassifier struct {
+	adapter      *TransactionAdapter
+	instructions []model.ClassifiedInstruction            // 按执行顺序展开的全部指令
+	byProgram    map[string][]model.ClassifiedInstruction // programID -> 指令列表
+	innerGroups  map[int][]model.ClassifiedInstruction    // outerIndex -> 内层指令
+	programIDs   []string                                 // 按首次出现顺序排列的程序 ID
+}
+
+// NewInstructionClassifier 创建指令分类器
+func NewInstructionClassifier(adapter *TransactionAdapter) *InstructionClassifier {
+	classifier := &InstructionClassifier{
+		adapter:     adapter,
+		byProgram:   make(map[string][]model.ClassifiedInstruction),
+		innerGroups: make(map[int][]model.ClassifiedInstruction),
+	}
+
+	tx := adapter.Transaction()
+	inner := make(map[int][]model.TransactionInstruction)
+	if tx.Meta != nil {
+		for _, group := range tx.Meta.InnerInstructions {
+			inner[group.Index] = append(inner[group.Index], group.Instructions...)
+		}
+	}
+
+	for outerIndex, instruction := range tx.Transaction.Message.Instructions {
+		classifier.add(model.ClassifiedInstruction{
+			Instruction: instruction,
+			ProgramID:   adapter.GetAccountKey(instruction.ProgramIdIndex),
+			OuterIndex:  outerIndex,
+		})
+
+		for innerIndex, innerInstruction := range inner[outerIndex] {
+			index := innerIndex
+			classified := model.ClassifiedInstruction{
+				Instruction: innerInstruction,
+				ProgramID:   adapter.GetAccountKey(innerInstruction.ProgramIdIndex),
+				OuterIndex:  outerIndex,
+				InnerIndex:  &index,
+			}
+			classifier.add(classified)
+			classifier.innerGroups[outerIndex] = append(classifier.innerGroups[outerIndex], classified)
+		}
+	}
+
+	return classifier
+}
+
+// add 记录一条分类指令
+func (c *InstructionClassifier) add(instruction model.ClassifiedInstruction) {
+	if _, exists := c.byProgram[instruction.ProgramID]; !exists {
+		c.programIDs = append(c.programIDs, instruction.ProgramID)
+	}
+	c.byProgram[instruction.ProgramID] = append(c.byProgram[instruction.ProgramID], instruction)
+	c.instructions = append(c.instructions, instruction)
+}
+
+// GetInstructions 获取指定程序的所有指令（含内层 CPI 调用）
+func (c *InstructionClassifier) GetInstructions(programID string) []model.ClassifiedInstruction {
+	return c.byProgram[programID]
+}
+
+// GetAllInstructions 获取按执行顺序展开的全部指令
+func (c *InstructionClassifier) GetAllInstructions() []model.ClassifiedInstruction {
+	return c.instructions
+}
+
+// GetAllProgramIDs 获取交易涉及的全部程序 ID（按首次出现顺序）
+func (c *InstructionClassifier) GetAllProgramIDs() []string {
+	return c.programIDs
+}
+
+// GetInnerInstructions 获取某条指令触发的内层 CPI 指令
+// 外层指令返回其全部内层指令；内层指令依据 stackHeight 返回其嵌套调用，缺少 stackHeight 时返回空
+func (c *InstructionClassifier) GetInnerInstructions(parent model.ClassifiedInstruction) []model.ClassifiedInstruction {
+	group := c.innerGroups[parent.OuterIndex]
+	if parent.InnerIndex == nil {
+		return group
+	}
+
+	start := *parent.InnerIndex
+	if start >= len(group) {
+		return nil
+	}
+	parentHeight := GetInstruction(group[start]).StackHeight
+	if parentHeight == nil {
+		return nil
+	}
+
+	end := start + 1
+	for end < len(group) {
+		height := GetInstruction(group[end]).StackHeight
+		if height == nil || *height <= *parentHeight {
+			break
+		}
+		end++
+	}
+	return group[start+1 : end]
+}
+
+// GetInstruction 取出分类指令中的原始指令
+func GetInstruction(classified model.ClassifiedInstruction) model.TransactionInstruction {
+	if instruction, ok := classified.Instruction.(model.TransactionInstruction); ok {
+		return instruction
+	}
+	return model.TransactionInstruction{}
+}
+
+// GetInstructionAccounts 解析指令引用的账户地址
+func (c *InstructionClassifier) GetInstructionAccounts(classified model.ClassifiedInstruction) []string {
+	instruction := GetInstruction(classified)
+	accounts := make([]string, len(instruction.Accounts))
+	for i, index := range instruction.Accounts {
+		accounts[i] = c.adapter.GetAccountKey(index)
+	}
+	return accounts
+}
+
+// GetIDX 生成指令位置标识：外层指令为 "外层索引"，内层指令为 "外层索引-内层索引"
+func GetIDX(classified model.ClassifiedInstruction) string {
+	return formatIDX(classified.OuterIndex, classified.InnerIndex)
+}
+
+// GetInstructionByIDX 按位置标识查找指令
+func (c *InstructionClassifier) GetInstructionByIDX(idx string) (model.ClassifiedInstruction, bool) {
+	for _, instruction := range c.instructions {
+		if GetIDX(instruction) == idx {
+			return instruction, true
+		}
+	}
+	return model.ClassifiedInstruction{}, false
+}
+
+// IsNestedIn 判断 child 是否为 parent 触发的内层调用
+func (c *InstructionClassifier) IsNestedIn(child, parent model.ClassifiedInstruction) bool {
+	if child.OuterIndex != parent.OuterIndex || child.InnerIndex == nil {
+		return false
+	}
+	if parent.InnerIndex == nil {
+		return true
+	}
+	nested := len(c.GetInnerInstructions(parent))
+	return *child.InnerIndex > *parent.InnerIndex && *child.InnerIndex <= *parent.InnerIndex+nested
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestInstructionClassifier_LoadedAddressesAndNesting(t *testing.T) {
+	jupiter := config.DEX_PROGRAMS["JUPITER"].ID
+	raydium := config.DEX_PROGRAMS["RAYDIUM_V4"].ID
+	tokenProgram := "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+
+	tx := &model.TransactionInfo{}
+	tx.Transaction.Message.AccountKeys = []string{testSigner, jupiter}
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		{ProgramIdIndex: 1},
+	}
+	tx.Meta = &model.TransactionMeta{
+		// v0 交易：Raydium 通过查找表加载（可写），Token Program 为只读
+		LoadedAddresses: &model.LoadedAddresses{
+			Writable: []string{raydium},
+			Readonly: []string{tokenProgram},
+		},
+		InnerInstructions: []model.InnerInstruction{
+			{Index: 0, Instructions: []model.TransactionInstruction{
+				{ProgramIdIndex: 2, StackHeight: intPtr(2)},
+				{ProgramIdIndex: 3, StackHeight: intPtr(3)},
+				{ProgramIdIndex: 3, StackHeight: intPtr(3)},
+				{ProgramIdIndex: 1, StackHeight: intPtr(2)},
+			}},
+		},
+	}
+
+	classifier := NewInstructionClassifier(NewTransactionAdapter(tx, 1, 1))
+
+	programIDs := classifier.GetAllProgramIDs()
+	if len(programIDs) != 3 || programIDs[0] != jupiter || programIDs[1] != raydium || programIDs[2] != tokenProgram {
+		t.Fatalf("unexpected program ids: %v", programIDs)
+	}
+
+	raydiumInstructions := classifier.GetInstructions(raydium)
+	if len(raydiumInstructions) != 1 || GetIDX(raydiumInstructions[0]) != "0-0" {
+		t.Fatalf("unexpected raydium instructions: %+v", raydiumInstructions)
+	}
+
+	if inner := classifier.GetInnerInstructions(raydiumInstructions[0]); len(inner) != 2 {
+		t.Fatalf("expected 2 nested token instructions, got %d", len(inner))
+	}
+
+	if inner := classifier.GetInnerInstructions(classifier.GetInstructions(jupiter)[0]); len(inner) != 4 {
+		t.Fatalf("expected 4 inner instructions under outer jupiter, got %d", len(inner))
+	}
+}
@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// JUPITER_ROUTE Jupiter 聚合交易的路由名称
+const JUPITER_ROUTE = "Jupiter"
+
+// Jupiter v6 路由指令与事件标识
+var (
+	jupiterRouteDiscriminators = [][]byte{
+		AnchorDiscriminator("route"),
+		AnchorDiscriminator("route_with_token_ledger"),
+		AnchorDiscriminator("shared_accounts_route"),
+		AnchorDiscriminator("shared_accounts_route_with_token_ledger"),
+		AnchorDiscriminator("exact_out_route"),
+		AnchorDiscriminator("shared_accounts_exact_out_route"),
+	}
+
+	jupiterSwapEventDiscriminator = AnchorEventDiscriminator("SwapEvent")
+)
+
+func init() {
+	jupiter := config.DEX_PROGRAMS["JUPITER"].ID
+	RegisterDexParser(jupiter, NewJupiterParser(jupiter))
+}
+
+// jupiterSwapEvent Jupiter 每一跳发出的 SwapEvent
+type jupiterSwapEvent struct {
+	AMM          string
+	InputMint    string
+	InputAmount  uint64
+	OutputMint   string
+	OutputAmount uint64
+}
+
+// JupiterParser Jupiter v6 路由解析器：将一次路由的多跳 SwapEvent 合并为一笔用户交易
+// 各跳底层 AMM 的交易由对应协议解析器产出，并在 TransactionParser 中挂到 TradeInfo.Hops
+type JupiterParser struct {
+	programID string
+}
+
+// NewJupiterParser 创建 Jupiter 解析器
+func NewJupiterParser(programID string) *JupiterParser {
+	return &JupiterParser{programID: programID}
+}
+
+// ProcessTrades 解析路由指令
+func (p *JupiterParser) ProcessTrades(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.TradeInfo {
+	events := GetAnchorEvents(adapter, classifier, p.programID)
+	used := make([]bool, len(events))
+
+	trades := []model.TradeInfo{}
+	for _, instruction := range classifier.GetInstructions(p.programID) {
+		if !p.isRoute(instruction) {
+			continue
+		}
+
+		// 自调用事件按嵌套关系归属；日志事件无法区分指令，全部归属第一条路由指令
+		var swapEvents []jupiterSwapEvent
+		for i, event := range events {
+			if used[i] || !event.Is(jupiterSwapEventDiscriminator) {
+				continue
+			}
+			if event.Instruction != nil && !classifier.IsNestedIn(*event.Instruction, instruction) {
+				continue
+			}
+			if decoded, err := decodeJupiterSwapEvent(event.Payload()); err == nil {
+				used[i] = true
+				swapEvents = append(swapEvents, decoded)
+			}
+		}
+
+		var trade *model.TradeInfo
+		if len(swapEvents) > 0 {
+			trade = p.buildTradeFromEvents(adapter, instruction, swapEvents)
+		} else {
+			trade = p.buildTradeFromTransfers(adapter, classifier, instruction)
+		}
+		if trade == nil {
+			continue
+		}
+		trade.Route = JUPITER_ROUTE
+		trades = append(trades, *trade)
+	}
+	return trades
+}
+
+// buildTradeFromEvents 合并多跳事件：首跳输入代币为卖出，末跳输出代币为买入，中间代币相互抵消
+func (p *JupiterParser) buildTradeFromEvents(adapter *TransactionAdapter, instruction model.ClassifiedInstruction, events []jupiterSwapEvent) *model.TradeInfo {
+	inputs := map[string]*big.Int{}
+	outputs := map[string]*big.Int{}
+	amms := make([]string, 0, len(events))
+	for _, event := range events {
+		addAmount(inputs, event.InputMint, event.InputAmount)
+		addAmount(outputs, event.OutputMint, event.OutputAmount)
+		amms = append(amms, getAMMName(event.AMM))
+	}
+
+	inMint := events[0].InputMint
+	outMint := events[len(events)-1].OutputMint
+
+	inAmount := new(big.Int).Set(inputs[inMint])
+	outAmount := new(big.Int).Set(outputs[outMint])
+	if inMint != outMint {
+		// 拆单路由中中间代币既是某跳输出又是另一跳输入，只保留净额
+		if produced, ok := outputs[inMint]; ok && inAmount.Cmp(produced) > 0 {
+			inAmount.Sub(inAmount, produced)
+		}
+		if consumed, ok := inputs[outMint]; ok && outAmount.Cmp(consumed) > 0 {
+			outAmount.Sub(outAmount, consumed)
+		}
+	}
+
+	trade := NewTradeInfo(adapter, instruction, inMint, inAmount, outMint, outAmount)
+	if trade != nil {
+		trade.AMMs = amms
+	}
+	return trade
+}
+
+// buildTradeFromTransfers 缺少 SwapEvent 时根据路由指令内层转账推断，AMMs 取内层调用的已知 DEX
+func (p *JupiterParser) buildTradeFromTransfers(adapter *TransactionAdapter, classifier *InstructionClassifier, instruction model.ClassifiedInstruction) *model.TradeInfo {
+	trade := BuildTradeFromTransfers(adapter, instruction, GetTransfersOf(adapter, classifier, instruction), adapter.Signer())
+	if trade == nil {
+		return nil
+	}
+
+	amms := []string{}
+	for _, inner := range classifier.GetInnerInstructions(instruction) {
+		if inner.ProgramID == p.programID {
+			continue
+		}
+		if _, ok := config.GetDexProgramByID(inner.ProgramID); ok {
+			amms = append(amms, config.GetProgramName(inner.ProgramID))
+		}
+	}
+	if len(amms) > 0 {
+		trade.AMMs = amms
+	}
+	return trade
+}
+
+// isRoute 判断是否为路由指令
+func (p *JupiterParser) isRoute(instruction model.ClassifiedInstruction) bool {
+	data, err := util.Base58Decode(GetInstruction(instruction).Data)
+	if err != nil {
+		return false
+	}
+	for _, discriminator := range jupiterRouteDiscriminators {
+		if bytes.HasPrefix(data, discriminator) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeJupiterSwapEvent 解码 SwapEvent：amm, input_mint, input_amount, output_mint, output_amount
+func decodeJupiterSwapEvent(data []byte) (jupiterSwapEvent, error) {
+	reader := NewBinaryReader(data)
+	var event jupiterSwapEvent
+	var err error
+
+	if event.AMM, err = reader.ReadPubkey(); err != nil {
+		return event, err
+	}
+	if event.InputMint, err = reader.ReadPubkey(); err != nil {
+		return event, err
+	}
+	if event.InputAmount, err = reader.ReadU64(); err != nil {
+		return event, err
+	}
+	if event.OutputMint, err = reader.ReadPubkey(); err != nil {
+		return event, err
+	}
+	if event.OutputAmount, err = reader.ReadU64(); err != nil {
+		return event, err
+	}
+	return event, nil
+}
+
+// addAmount 按代币累加数量
+func addAmount(amounts map[string]*big.Int, mint string, amount uint64) {
+	if existing, ok := amounts[mint]; ok {
+		existing.Add(existing, new(big.Int).SetUint64(amount))
+		return
+	}
+	amounts[mint] = new(big.Int).SetUint64(amount)
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// newJupiterTransaction 将测试交易中的 Raydium 兑换包装为 Jupiter 路由的一跳
+func newJupiterTransaction(t *testing.T) *model.TransactionInfo {
+	tx := newTestTransaction()
+	tx.Transaction.Message.AccountKeys = append(tx.Transaction.Message.AccountKeys, config.DEX_PROGRAMS["JUPITER"].ID)
+	tx.Meta.PreBalances = append(tx.Meta.PreBalances, 1)
+	tx.Meta.PostBalances = append(tx.Meta.PostBalances, 1)
+
+	raydiumSwap := tx.Transaction.Message.Instructions[0]
+	raydiumSwap.StackHeight = intPtr(2)
+	transfers := tx.Meta.InnerInstructions[0].Instructions
+	for i := range transfers {
+		transfers[i].StackHeight = intPtr(3)
+	}
+
+	swapEvent := append([]byte{}, anchorEventInstructionTag...)
+	swapEvent = append(swapEvent, jupiterSwapEventDiscriminator...)
+	swapEvent = append(swapEvent, mustBase58Decode(t, config.DEX_PROGRAMS["RAYDIUM_V4"].ID)...)
+	swapEvent = append(swapEvent, mustBase58Decode(t, config.WSOL_ADDRESS)...)
+	swapEvent = binary.LittleEndian.AppendUint64(swapEvent, 1_000_000_000)
+	swapEvent = append(swapEvent, mustBase58Decode(t, testMint)...)
+	swapEvent = binary.LittleEndian.AppendUint64(swapEvent, 1_000_000_000)
+
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		{ProgramIdIndex: 9, Accounts: []int{6, 0, 1}, Data: util.Base58Encode(append(AnchorDiscriminator("route"), make([]byte, 24)...))},
+	}
+	inner := append([]model.TransactionInstruction{raydiumSwap}, transfers...)
+	inner = append(inner, model.TransactionInstruction{ProgramIdIndex: 9, Accounts: []int{9}, StackHeight: intPtr(2), Data: util.Base58Encode(swapEvent)})
+	tx.Meta.InnerInstructions = []model.InnerInstruction{{Index: 0, Instructions: inner}}
+	return tx
+}
+
+func TestJupiterRouteAggregatesHops(t *testing.T) {
+	result := NewTransactionParser(nil).ParseAll(newJupiterTransaction(t), 100, 1700000000)
+	if len(result.Trades) != 1 {
+		t.Fatalf("expected 1 top level trade, got %d", len(result.Trades))
+	}
+
+	trade := result.Trades[0]
+	if trade.Route != JUPITER_ROUTE || trade.AMM != "Jupiter" || trade.IDX != "0" {
+		t.Fatalf("unexpected route trade: %+v", trade)
+	}
+	if len(trade.AMMs) != 1 || trade.AMMs[0] != "Raydium V4" {
+		t.Fatalf("unexpected amms: %v", trade.AMMs)
+	}
+	if trade.TokenInMint != config.WSOL_ADDRESS || trade.TokenOutMint != testMint || trade.TokenOutAmount != "1000000000" {
+		t.Fatalf("unexpected route amounts: %+v", trade)
+	}
+
+	if len(trade.Hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(trade.Hops))
+	}
+	if hop := trade.Hops[0]; hop.PoolAddress != testPool || hop.IDX != "0-0" || hop.AMM != "Raydium V4" {
+		t.Fatalf("unexpected hop: %+v", hop)
+	}
+}
+
+func TestNestTradesKeepsExecutionOrder(t *testing.T) {
+	classifier := NewInstructionClassifier(NewTransactionAdapter(newJupiterTransaction(t), 1, 1))
+	trades := []model.TradeInfo{
+		{IDX: "0-0", ProgramID: config.DEX_PROGRAMS["RAYDIUM_V4"].ID},
+		{IDX: "0", ProgramID: config.DEX_PROGRAMS["JUPITER"].ID},
+	}
+
+	nested := nestTrades(classifier, trades)
+	if len(nested) != 1 || nested[0].IDX != "0" || len(nested[0].Hops) != 1 || nested[0].Hops[0].IDX != "0-0" {
+		t.Fatalf("unexpected nesting: %+v", nested)
+	}
+}
+
+func TestBotProgramDoesNotWrapDecodedTrade(t *testing.T) {
+	tx := newJupiterTransaction(t)
+	tx.Transaction.Message.AccountKeys[9] = config.DEX_PROGRAMS["PHOTON"].ID
+
+	for _, tryUnknown := range []bool{false, true} {
+		result := NewTransactionParser(&model.ParseConfig{TryUnknownDEX: tryUnknown}).ParseAll(tx, 100, 1700000000)
+		if len(result.Trades) != 1 {
+			t.Fatalf("TryUnknownDEX=%v: expected 1 trade, got %d", tryUnknown, len(result.Trades))
+		}
+		if trade := result.Trades[0]; trade.AMM != "Raydium V4" || trade.PoolAddress != testPool || trade.IDX != "0-0" || len(trade.Hops) != 0 {
+			t.Fatalf("TryUnknownDEX=%v: unexpected trade: %+v", tryUnknown, trade)
+		}
+	}
+}
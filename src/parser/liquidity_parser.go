@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// LiquidityParser 单个协议的流动性事件解析器
+type LiquidityParser interface {
+	// ProcessLiquidity 解析交易中该协议的建池、加池与撤池事件
+	ProcessLiquidity(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.PoolEvent
+}
+
+// liquidityParsers programID -> 流动性解析器，在各协议文件的 init 中注册
+var liquidityParsers = map[string]LiquidityParser{}
+
+// RegisterLiquidityParser 注册流动性解析器，同一程序 ID 重复注册时覆盖
+func RegisterLiquidityParser(programID string, parser LiquidityParser) {
+	liquidityParsers[programID] = parser
+}
+
+// GetLiquidityParser 获取程序对应的流动性解析器
+func GetLiquidityParser(programID string) (LiquidityParser, bool) {
+	parser, ok := liquidityParsers[programID]
+	return parser, ok
+}
+
+// liquidityInstruction 流动性指令标识、事件类型与关键账户位置
+type liquidityInstruction struct {
+	discriminator []byte
+	eventType     string
+	poolIndex     int
+	mintIndexes   []int // 建池指令中 token0/token1 mint 的账户位置，为空时按转账顺序确定
+}
+
+// LiquidityInstructionParser 按指令标识识别流动性指令，代币数量取自内层代币转账
+type LiquidityInstructionParser struct {
+	programID    string
+	instructions []liquidityInstruction
+}
+
+// NewLiquidityInstructionParser 创建按指令标识匹配的流动性解析器
+func NewLiquidityInstructionParser(programID string) *LiquidityInstructionParser {
+	return &LiquidityInstructionParser{programID: programID}
+}
+
+// With 登记加池或撤池指令
+func (p *LiquidityInstructionParser) With(eventType string, poolIndex int, discriminators ...[]byte) *LiquidityInstructionParser {
+	for _, discriminator := range discriminators {
+		p.instructions = append(p.instructions, liquidityInstruction{discriminator: discriminator, eventType: eventType, poolIndex: poolIndex})
+	}
+	return p
+}
+
+// WithCreate 登记建池指令，mint0Index/mint1Index 为两个代币 mint 的账户位置
+func (p *LiquidityInstructionParser) WithCreate(poolIndex, mint0Index, mint1Index int, discriminators ...[]byte) *LiquidityInstructionParser {
+	for _, discriminator := range discriminators {
+		p.instructions = append(p.instructions, liquidityInstruction{
+			discriminator: discriminator,
+			eventType:     model.PoolEventTypeCreate,
+			poolIndex:     poolIndex,
+			mintIndexes:   []int{mint0Index, mint1Index},
+		})
+	}
+	return p
+}
+
+// ProcessLiquidity 解析该程序全部流动性指令
+func (p *LiquidityInstructionParser) ProcessLiquidity(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.PoolEvent {
+	events := []model.PoolEvent{}
+	for _, instruction := range classifier.GetInstructions(p.programID) {
+		matched, ok := p.match(instruction)
+		if !ok {
+			continue
+		}
+		accounts := classifier.GetInstructionAccounts(instruction)
+		if len(accounts) <= matched.poolIndex {
+			continue
+		}
+
+		mints, amounts := liquidityAmounts(GetTransfersOf(adapter, classifier, instruction), matched.eventType, adapter.Signer())
+		if matched.eventType != model.PoolEventTypeCreate && len(mints) == 0 {
+			continue
+		}
+		if len(matched.mintIndexes) == 2 && len(accounts) > matched.mintIndexes[0] && len(accounts) > matched.mintIndexes[1] {
+			mints = []string{accounts[matched.mintIndexes[0]], accounts[matched.mintIndexes[1]]}
+		}
+
+		event := model.PoolEvent{
+			Signature:    adapter.Signature(),
+			Type:         matched.eventType,
+			Signer:       adapter.Signer(),
+			PoolAddress:  accounts[matched.poolIndex],
+			SlotNumber:   adapter.Slot(),
+			BlockTime:    adapter.BlockTime(),
+			ProgramID:    instruction.ProgramID,
+			AMM:          getAMMName(instruction.ProgramID),
+			Token0Amount: "0",
+			Token1Amount: "0",
+			IDX:          GetIDX(instruction),
+		}
+		if len(mints) > 0 {
+			event.Token0Mint = mints[0]
+			event.Token0Decimals = adapter.TokenDecimals(mints[0])
+			if amount, ok := amounts[mints[0]]; ok {
+				event.Token0Amount = amount.String()
+			}
+		}
+		if len(mints) > 1 {
+			event.Token1Mint = mints[1]
+			event.Token1Decimals = adapter.TokenDecimals(mints[1])
+			if amount, ok := amounts[mints[1]]; ok {
+				event.Token1Amount = amount.String()
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// match 匹配流动性指令
+func (p *LiquidityInstructionParser) match(instruction model.ClassifiedInstruction) (liquidityInstruction, bool) {
+	data, err := util.Base58Decode(GetInstruction(instruction).Data)
+	if err != nil {
+		return liquidityInstruction{}, false
+	}
+	for _, candidate := range p.instructions {
+		if bytes.HasPrefix(data, candidate.discriminator) {
+			return candidate, true
+		}
+	}
+	return liquidityInstruction{}, false
+}
+
+// liquidityAmounts 汇总用户转入（建池/加池）或转出（撤池）池子的代币数量，按首次出现顺序返回 mint
+// 只统计代币程序转账，建池时支付给协议的 SOL 手续费不计入
+func liquidityAmounts(transfers []model.TransferData, eventType, user string) ([]string, map[string]*big.Int) {
+	var mints []string
+	amounts := map[string]*big.Int{}
+	for _, transfer := range transfers {
+		if !isTokenProgram(transfer.ProgramID) || !isSwapTransfer(transfer) {
+			continue
+		}
+		if eventType == model.PoolEventTypeRemove {
+			if transfer.Info.DestinationOwner != user {
+				continue
+			}
+		} else if transfer.Info.SourceOwner != user {
+			continue
+		}
+
+		mint := transfer.Info.Mint
+		if _, ok := amounts[mint]; !ok {
+			mints = append(mints, mint)
+			amounts[mint] = big.NewInt(0)
+		}
+		amounts[mint].Add(amounts[mint], parseRawAmount(transfer.Info.Amount))
+	}
+	return mints, amounts
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// newLiquidityTransaction 构造签名者向 CPMM 池存入 1 WSOL 与 1000 个代币的交易
+func newLiquidityTransaction() *model.TransactionInfo {
+	const (
+		userWSOL   = "userWsol11111111111111111111111111111111111"
+		userToken  = "userTokenAccount1111111111111111111111111111"
+		vaultWSOL  = "vaultWsol1111111111111111111111111111111111"
+		vaultToken = "vaultToken111111111111111111111111111111111"
+		authority  = "cpmmAuthority11111111111111111111111111111"
+	)
+
+	tx := &model.TransactionInfo{}
+	tx.Transaction.Signatures = []string{"liquiditySignature"}
+	tx.Transaction.Message.AccountKeys = []string{
+		testSigner, userWSOL, userToken, vaultWSOL, vaultToken, authority, testPool,
+		config.TOKEN_PROGRAM_ID, config.DEX_PROGRAMS["RAYDIUM_CPMM"].ID,
+	}
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		// deposit: owner, authority, pool state, ...
+		{ProgramIdIndex: 8, Accounts: []int{0, 5, 6, 1, 2, 3, 4}, Data: util.Base58Encode(append(AnchorDiscriminator("deposit"), make([]byte, 24)...))},
+	}
+
+	balance := func(index int, mint, owner string) model.TokenBalance {
+		decimals := 6
+		if mint == config.WSOL_ADDRESS {
+			decimals = 9
+		}
+		return model.TokenBalance{AccountIndex: index, Mint: mint, Owner: owner, UiTokenAmount: model.UiTokenAmount{Amount: "0", Decimals: decimals}}
+	}
+	tx.Meta = &model.TransactionMeta{
+		Fee:          5000,
+		PreBalances:  make([]uint64, 9),
+		PostBalances: make([]uint64, 9),
+		InnerInstructions: []model.InnerInstruction{{Index: 0, Instructions: []model.TransactionInstruction{
+			{ProgramIdIndex: 7, Accounts: []int{1, 3, 0}, Data: encodeTokenInstruction(tokenInstructionTransfer, 1_000_000_000)},
+			{ProgramIdIndex: 7, Accounts: []int{2, 4, 0}, Data: encodeTokenInstruction(tokenInstructionTransfer, 1_000_000_000)},
+		}}},
+		PostTokenBalances: []model.TokenBalance{
+			balance(1, config.WSOL_ADDRESS, testSigner),
+			balance(2, testMint, testSigner),
+			balance(3, config.WSOL_ADDRESS, authority),
+			balance(4, testMint, authority),
+		},
+	}
+	return tx
+}
+
+func TestCPMMDepositProducesAddEvent(t *testing.T) {
+	result := NewTransactionParser(nil).ParseAll(newLiquidityTransaction(), 100, 1700000000)
+	if len(result.Trades) != 0 {
+		t.Fatalf("deposit should not produce trades, got %d", len(result.Trades))
+	}
+	if len(result.Liquidities) != 1 {
+		t.Fatalf("expected 1 liquidity event, got %d", len(result.Liquidities))
+	}
+
+	event := result.Liquidities[0]
+	if event.Type != model.PoolEventTypeAdd || event.PoolAddress != testPool || event.AMM != "Raydium CPMM" || event.IDX != "0" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if event.Token0Mint != config.WSOL_ADDRESS || event.Token0Amount != "1000000000" || event.Token0Decimals != 9 {
+		t.Fatalf("unexpected token0: %+v", event)
+	}
+	if event.Token1Mint != testMint || event.Token1Amount != "1000000000" || event.Token1Decimals != 6 {
+		t.Fatalf("unexpected token1: %+v", event)
+	}
+
+	lp := result.Result.Liquidities[0]
+	if lp.Token0Amount != 1 || lp.Token1Amount != 1000 {
+		t.Fatalf("unexpected ui amounts: %+v", lp)
+	}
+}
+
+func TestCPMMWithdrawIgnoresTransfersFromUser(t *testing.T) {
+	tx := newLiquidityTransaction()
+	tx.Transaction.Message.Instructions[0].Data = util.Base58Encode(append(AnchorDiscriminator("withdraw"), make([]byte, 24)...))
+
+	// 撤池时只统计转给用户的代币，用户转出的转账不构成撤池数量
+	if result := NewTransactionParser(nil).ParseAll(tx, 100, 1700000000); len(result.Liquidities) != 0 {
+		t.Fatalf("expected no remove event, got %+v", result.Liquidities)
+	}
+}
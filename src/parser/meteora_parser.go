@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+// Meteora 兑换指令中池地址所在的账户位置
+const (
+	meteoraDLMMPoolIndex  = 0 // lb pair, bin array bitmap extension, reserve x, reserve y, ...
+	meteoraPoolsPoolIndex = 0 // pool, user source token, user destination token, ...
+	meteoraDAMMPoolIndex  = 1 // pool authority, pool, ...
+)
+
+// Meteora DLMM 流动性指令中池地址与代币 mint 所在的账户位置
+const (
+	meteoraDLMMCreatePoolIndex    = 0 // lb pair, bin array bitmap extension, token mint x, token mint y, ...
+	meteoraDLMMCreateMintXIndex   = 2
+	meteoraDLMMCreateMintYIndex   = 3
+	meteoraDLMMLiquidityPoolIndex = 1 // position, lb pair, ...
+)
+
+func init() {
+	dlmm := config.DEX_PROGRAMS["METEORA"].ID
+	RegisterDexParser(dlmm, NewSwapInstructionParser(dlmm, meteoraDLMMPoolIndex,
+		AnchorDiscriminator("swap"),
+		AnchorDiscriminator("swap_exact_out"),
+		AnchorDiscriminator("swap_with_price_impact"),
+		AnchorDiscriminator("swap2"),
+		AnchorDiscriminator("swap_exact_out2"),
+		AnchorDiscriminator("swap_with_price_impact2"),
+	))
+
+	RegisterLiquidityParser(dlmm, NewLiquidityInstructionParser(dlmm).
+		WithCreate(meteoraDLMMCreatePoolIndex, meteoraDLMMCreateMintXIndex, meteoraDLMMCreateMintYIndex,
+			AnchorDiscriminator("initialize_lb_pair"),
+			AnchorDiscriminator("initialize_customizable_permissionless_lb_pair")).
+		With(model.PoolEventTypeAdd, meteoraDLMMLiquidityPoolIndex,
+			AnchorDiscriminator("add_liquidity"),
+			AnchorDiscriminator("add_liquidity2"),
+			AnchorDiscriminator("add_liquidity_by_weight"),
+			AnchorDiscriminator("add_liquidity_by_strategy"),
+			AnchorDiscriminator("add_liquidity_by_strategy2"),
+			AnchorDiscriminator("add_liquidity_by_strategy_one_side"),
+			AnchorDiscriminator("add_liquidity_one_side"),
+			AnchorDiscriminator("add_liquidity_one_side_precise")).
+		With(model.PoolEventTypeRemove, meteoraDLMMLiquidityPoolIndex,
+			AnchorDiscriminator("remove_liquidity"),
+			AnchorDiscriminator("remove_liquidity2"),
+			AnchorDiscriminator("remove_all_liquidity"),
+			AnchorDiscriminator("remove_liquidity_by_range"),
+			AnchorDiscriminator("remove_liquidity_by_range2")))
+
+	pools := config.DEX_PROGRAMS["METEORA_POOLS"].ID
+	RegisterDexParser(pools, NewSwapInstructionParser(pools, meteoraPoolsPoolIndex,
+		AnchorDiscriminator("swap"),
+	))
+
+	damm := config.DEX_PROGRAMS["METEORA_DAMM"].ID
+	RegisterDexParser(damm, NewSwapInstructionParser(damm, meteoraDAMMPoolIndex,
+		AnchorDiscriminator("swap"),
+		AnchorDiscriminator("swap2"),
+	))
+}
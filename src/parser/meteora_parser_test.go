@@ -0,0 +1,21 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/util"
+)
+
+func TestMeteoraDLMMSwap(t *testing.T) {
+	tx := newTestTransaction()
+	tx.Transaction.Message.AccountKeys[2] = config.DEX_PROGRAMS["METEORA"].ID
+	// swap: lb pair, bin array bitmap extension, reserve x, reserve y, ...
+	tx.Transaction.Message.Instructions[0].Accounts = []int{8, 7, 3, 4, 1, 0}
+	tx.Transaction.Message.Instructions[0].Data = util.Base58Encode(append(AnchorDiscriminator("swap_exact_out"), make([]byte, 16)...))
+
+	result := NewTransactionParser(nil).ParseAll(tx, 100, 1700000000)
+	if len(result.Trades) != 1 || result.Trades[0].PoolAddress != testPool || result.Trades[0].AMM != "Meteora DLMM" {
+		t.Fatalf("unexpected trades: %+v", result.Trades)
+	}
+}
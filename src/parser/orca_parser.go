@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+// Orca Whirlpool 指令中池地址所在的账户位置
+const (
+	orcaSwapPoolIndex        = 2 // token program, token authority, whirlpool, ...
+	orcaSwapV2PoolIndex      = 4 // token program a, token program b, memo program, token authority, whirlpool, ...
+	orcaTwoHopPoolTwoIndex   = 3 // token program, token authority, whirlpool one, whirlpool two, ...
+	orcaTwoHopV2PoolTwoIndex = 1 // whirlpool one, whirlpool two, ...
+)
+
+// Orca Whirlpool 流动性指令中池地址与代币 mint 所在的账户位置
+const (
+	orcaCreatePoolIndex    = 4 // whirlpools config, token mint a, token mint b, funder, whirlpool, ...
+	orcaCreateV2PoolIndex  = 6 // whirlpools config, token mint a, token mint b, badge a, badge b, funder, whirlpool, ...
+	orcaCreateMintAIndex   = 1
+	orcaCreateMintBIndex   = 2
+	orcaLiquidityPoolIndex = 0 // whirlpool, ...
+)
+
+func init() {
+	orca := config.DEX_PROGRAMS["ORCA"].ID
+	RegisterDexParser(orca, NewOrcaParser(orca))
+
+	RegisterLiquidityParser(orca, NewLiquidityInstructionParser(orca).
+		WithCreate(orcaCreatePoolIndex, orcaCreateMintAIndex, orcaCreateMintBIndex, AnchorDiscriminator("initialize_pool")).
+		WithCreate(orcaCreateV2PoolIndex, orcaCreateMintAIndex, orcaCreateMintBIndex, AnchorDiscriminator("initialize_pool_v2")).
+		With(model.PoolEventTypeAdd, orcaLiquidityPoolIndex,
+			AnchorDiscriminator("increase_liquidity"),
+			AnchorDiscriminator("increase_liquidity_v2")).
+		With(model.PoolEventTypeRemove, orcaLiquidityPoolIndex,
+			AnchorDiscriminator("decrease_liquidity"),
+			AnchorDiscriminator("decrease_liquidity_v2")))
+}
+
+// OrcaParser Orca Whirlpool 解析器：swap/swapV2 为单池交易，twoHopSwap 合并为一笔路由交易
+type OrcaParser struct {
+	*SwapInstructionParser
+	twoHop *SwapInstructionParser
+}
+
+// NewOrcaParser 创建 Orca Whirlpool 解析器
+func NewOrcaParser(programID string) *OrcaParser {
+	return &OrcaParser{
+		SwapInstructionParser: NewSwapInstructionParser(programID, orcaSwapPoolIndex, AnchorDiscriminator("swap")).
+			WithSwap(orcaSwapV2PoolIndex, AnchorDiscriminator("swap_v2")),
+		// 两跳指令中第一个池紧挨在第二个池之前，这里只登记第二个池的位置
+		twoHop: NewSwapInstructionParser(programID, orcaTwoHopPoolTwoIndex, AnchorDiscriminator("two_hop_swap")).
+			WithSwap(orcaTwoHopV2PoolTwoIndex, AnchorDiscriminator("two_hop_swap_v2")),
+	}
+}
+
+// ProcessTrades 解析单池交易与两跳交易
+func (p *OrcaParser) ProcessTrades(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.TradeInfo {
+	trades := p.SwapInstructionParser.ProcessTrades(adapter, classifier)
+
+	for _, instruction := range classifier.GetInstructions(p.programID) {
+		poolTwoIndex, ok := p.twoHop.matchSwap(instruction)
+		if !ok {
+			continue
+		}
+		accounts := classifier.GetInstructionAccounts(instruction)
+		if len(accounts) <= poolTwoIndex {
+			continue
+		}
+		pools := []string{accounts[poolTwoIndex-1], accounts[poolTwoIndex]}
+
+		if trade := buildTwoHopTrade(adapter, instruction, GetTransfersOf(adapter, classifier, instruction), pools); trade != nil {
+			trades = append(trades, *trade)
+		}
+	}
+	return trades
+}
+
+// buildTwoHopTrade 两跳交易：顶层为用户首尾代币，Hops 记录每个池的成交
+// 第一跳为前两笔转账，第二跳为最后两笔转账（V2 中间代币直接在两个池之间划转时两跳共用中间转账）
+func buildTwoHopTrade(adapter *TransactionAdapter, instruction model.ClassifiedInstruction, transfers []model.TransferData, pools []string) *model.TradeInfo {
+	swapTransfers := make([]model.TransferData, 0, len(transfers))
+	for _, transfer := range transfers {
+		if isSwapTransfer(transfer) {
+			swapTransfers = append(swapTransfers, transfer)
+		}
+	}
+	if len(swapTransfers) < 3 {
+		return nil
+	}
+
+	trade := BuildTradeFromTransfers(adapter, instruction, swapTransfers, adapter.Signer())
+	if trade == nil {
+		return nil
+	}
+
+	n := len(swapTransfers)
+	hopTransfers := [][]model.TransferData{swapTransfers[:2], swapTransfers[n-2:]}
+	for i, pair := range hopTransfers {
+		hop := NewTradeInfo(adapter, instruction,
+			normalizeMint(pair[0].Info.Mint), parseRawAmount(pair[0].Info.Amount),
+			normalizeMint(pair[1].Info.Mint), parseRawAmount(pair[1].Info.Amount))
+		if hop == nil {
+			continue
+		}
+		hop.PoolAddress = pools[i]
+		trade.Hops = append(trade.Hops, *hop)
+	}
+
+	trade.Route = trade.AMM
+	trade.AMMs = []string{trade.AMM, trade.AMM}
+	return trade
+}
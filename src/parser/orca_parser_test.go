@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+func TestOrcaTwoHopSwap(t *testing.T) {
+	const (
+		usdcMint     = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+		poolOne      = "HJPjoWUrhoZzkNfRpHuieeFk9WcZWjwy6PBjZ81ngndJ"
+		poolTwo      = "Czfq3xZZDmsdGdUyrNLtRhGc47cXcZtLG4crryfu44zE"
+		userWSOL     = "userWsol11111111111111111111111111111111111"
+		userUSDC     = "userUsdc11111111111111111111111111111111111"
+		userToken    = "userToken1111111111111111111111111111111111"
+		poolOneVault = "poolOneVault111111111111111111111111111111"
+		poolOneUSDC  = "poolOneUsdc1111111111111111111111111111111"
+		poolTwoUSDC  = "poolTwoUsdc1111111111111111111111111111111"
+		poolTwoVault = "poolTwoVault111111111111111111111111111111"
+		poolAuth     = "whirlpoolAuthority1111111111111111111111111"
+	)
+
+	tx := &model.TransactionInfo{}
+	tx.Transaction.Signatures = []string{"orcaSignature"}
+	tx.Transaction.Message.AccountKeys = []string{
+		testSigner, userWSOL, userUSDC, userToken, poolOneVault, poolOneUSDC, poolTwoUSDC, poolTwoVault,
+		config.TOKEN_PROGRAM_ID, poolOne, poolTwo, config.DEX_PROGRAMS["ORCA"].ID, poolAuth,
+	}
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		{ProgramIdIndex: 11, Accounts: []int{8, 0, 9, 10}, Data: util.Base58Encode(append(AnchorDiscriminator("two_hop_swap"), make([]byte, 16)...))},
+	}
+
+	transfer := func(source, destination, owner int, amount uint64) model.TransactionInstruction {
+		return model.TransactionInstruction{ProgramIdIndex: 8, Accounts: []int{source, destination, owner}, Data: encodeTokenInstruction(tokenInstructionTransfer, amount)}
+	}
+	balance := func(index int, mint, owner string) model.TokenBalance {
+		return model.TokenBalance{AccountIndex: index, Mint: mint, Owner: owner, UiTokenAmount: model.UiTokenAmount{Amount: "0", Decimals: 6}}
+	}
+	tx.Meta = &model.TransactionMeta{
+		Fee:          5000,
+		PreBalances:  make([]uint64, 13),
+		PostBalances: make([]uint64, 13),
+		InnerInstructions: []model.InnerInstruction{{Index: 0, Instructions: []model.TransactionInstruction{
+			transfer(1, 4, 0, 1_000_000_000),
+			transfer(5, 2, 12, 150_000_000),
+			transfer(2, 6, 0, 150_000_000),
+			transfer(7, 3, 12, 42_000_000),
+		}}},
+		PostTokenBalances: []model.TokenBalance{
+			balance(1, config.WSOL_ADDRESS, testSigner),
+			balance(2, usdcMint, testSigner),
+			balance(3, testMint, testSigner),
+			balance(4, config.WSOL_ADDRESS, poolAuth),
+			balance(5, usdcMint, poolAuth),
+			balance(6, usdcMint, poolAuth),
+			balance(7, testMint, poolAuth),
+		},
+	}
+
+	result := NewTransactionParser(nil).ParseAll(tx, 100, 1700000000)
+	if len(result.Trades) != 1 {
+		t.Fatalf("expected 1 routed trade, got %d", len(result.Trades))
+	}
+
+	trade := result.Trades[0]
+	if trade.TokenInMint != config.WSOL_ADDRESS || trade.TokenInAmount != "1000000000" || trade.TokenOutMint != testMint || trade.TokenOutAmount != "42000000" {
+		t.Fatalf("unexpected routed trade: %+v", trade)
+	}
+	if trade.Route != "Orca Whirlpool" || len(trade.AMMs) != 2 {
+		t.Fatalf("unexpected route: %s %v", trade.Route, trade.AMMs)
+	}
+	if len(trade.Hops) != 2 || trade.Hops[0].PoolAddress != poolOne || trade.Hops[1].PoolAddress != poolTwo {
+		t.Fatalf("unexpected hops: %+v", trade.Hops)
+	}
+	if trade.Hops[0].TokenOutMint != usdcMint || trade.Hops[1].TokenInMint != usdcMint {
+		t.Fatalf("unexpected intermediate token: %+v", trade.Hops)
+	}
+}
@@ -0,0 +1,314 @@
+package parser
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// Pump.fun 代币固定为 6 位精度
+const PUMPFUN_TOKEN_DECIMALS uint8 = 6
+
+// Pump.fun 指令中联合曲线账户位置：global, fee recipient, mint, bonding curve, ...
+const (
+	pumpfunTradeBondingCurveIndex  = 3
+	pumpfunCreateBondingCurveIndex = 2 // mint, mint authority, bonding curve, ...
+	pumpswapPoolIndex              = 0 // pool, user, global config, ...
+	pumpswapCreateBaseMintIndex    = 3 // pool, global config, creator, base mint, quote mint, ...
+	pumpswapCreateQuoteMintIndex   = 4
+)
+
+// MoreEvents 中 Pump.fun 事件的键名
+const (
+	EVENT_PUMPFUN_TRADE  = "pumpfun_trade"
+	EVENT_PUMPFUN_CREATE = "pumpfun_create"
+)
+
+// Pump.fun 指令与事件标识
+var (
+	pumpfunBuyDiscriminator    = AnchorDiscriminator("buy")
+	pumpfunSellDiscriminator   = AnchorDiscriminator("sell")
+	pumpfunCreateDiscriminator = AnchorDiscriminator("create")
+
+	pumpfunTradeEventDiscriminator  = AnchorEventDiscriminator("TradeEvent")
+	pumpfunCreateEventDiscriminator = AnchorEventDiscriminator("CreateEvent")
+)
+
+func init() {
+	pumpfun := config.DEX_PROGRAMS["PUMP_FUN"].ID
+	RegisterDexParser(pumpfun, NewPumpfunParser(pumpfun))
+
+	pumpswap := config.DEX_PROGRAMS["PUMP_SWAP"].ID
+	RegisterDexParser(pumpswap, NewSwapInstructionParser(pumpswap, pumpswapPoolIndex,
+		pumpfunBuyDiscriminator,
+		pumpfunSellDiscriminator,
+	))
+
+	RegisterLiquidityParser(pumpswap, NewLiquidityInstructionParser(pumpswap).
+		WithCreate(pumpswapPoolIndex, pumpswapCreateBaseMintIndex, pumpswapCreateQuoteMintIndex, AnchorDiscriminator("create_pool")).
+		With(model.PoolEventTypeAdd, pumpswapPoolIndex, AnchorDiscriminator("deposit")).
+		With(model.PoolEventTypeRemove, pumpswapPoolIndex, AnchorDiscriminator("withdraw")))
+}
+
+// PumpfunParser Pump.fun 联合曲线解析器：买卖指令结合 TradeEvent 得到精确成交数量，CreateEvent 产出新代币
+type PumpfunParser struct {
+	programID string
+}
+
+// NewPumpfunParser 创建 Pump.fun 解析器
+func NewPumpfunParser(programID string) *PumpfunParser {
+	return &PumpfunParser{programID: programID}
+}
+
+// ProcessTrades 解析买卖交易
+func (p *PumpfunParser) ProcessTrades(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.TradeInfo {
+	trades := []model.TradeInfo{}
+	for _, match := range p.matchTradeEvents(adapter, classifier) {
+		event := match.event
+		mintAmount := new(big.Int).SetUint64(event.TokenAmount)
+		solAmount := new(big.Int).SetUint64(event.SolAmount)
+
+		var trade *model.TradeInfo
+		if event.IsBuy {
+			trade = NewTradeInfo(adapter, match.instruction, config.WSOL_ADDRESS, solAmount, event.Mint, mintAmount)
+		} else {
+			trade = NewTradeInfo(adapter, match.instruction, event.Mint, mintAmount, config.WSOL_ADDRESS, solAmount)
+		}
+		if trade == nil {
+			continue
+		}
+
+		if trade.TokenInDecimals == 0 && trade.TokenInMint == event.Mint {
+			trade.TokenInDecimals = PUMPFUN_TOKEN_DECIMALS
+		}
+		if trade.TokenOutDecimals == 0 && trade.TokenOutMint == event.Mint {
+			trade.TokenOutDecimals = PUMPFUN_TOKEN_DECIMALS
+		}
+		trade.PoolAddress = event.BondingCurve
+		trades = append(trades, *trade)
+	}
+	return trades
+}
+
+// ProcessEvents 输出 TradeEvent（含虚拟储备）与 CreateEvent
+func (p *PumpfunParser) ProcessEvents(adapter *TransactionAdapter, classifier *InstructionClassifier) map[string]interface{} {
+	events := map[string]interface{}{}
+
+	matches := p.matchTradeEvents(adapter, classifier)
+	if len(matches) > 0 {
+		tradeEvents := make([]model.PumpfunTradeEvent, 0, len(matches))
+		for _, match := range matches {
+			tradeEvents = append(tradeEvents, match.event)
+		}
+		events[EVENT_PUMPFUN_TRADE] = tradeEvents
+	}
+
+	if createEvents := p.createEvents(adapter, classifier); len(createEvents) > 0 {
+		events[EVENT_PUMPFUN_CREATE] = createEvents
+	}
+	return events
+}
+
+// ProcessTokens 将 CreateEvent 转换为新代币元数据
+func (p *PumpfunParser) ProcessTokens(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.ResTokenMetadataStruct {
+	tokens := []model.ResTokenMetadataStruct{}
+	for _, event := range p.createEvents(adapter, classifier) {
+		tokens = append(tokens, model.ResTokenMetadataStruct{
+			Mint:     event.Mint,
+			Symbol:   event.Symbol,
+			Name:     event.Name,
+			Decimals: PUMPFUN_TOKEN_DECIMALS,
+			URI:      event.URI,
+		})
+	}
+	return tokens
+}
+
+// pumpfunTradeMatch 买卖指令与其 TradeEvent 的配对
+type pumpfunTradeMatch struct {
+	instruction model.ClassifiedInstruction
+	event       model.PumpfunTradeEvent
+}
+
+// matchTradeEvents 将买卖指令与 TradeEvent 配对：自调用事件按嵌套关系匹配，日志事件按出现顺序匹配
+func (p *PumpfunParser) matchTradeEvents(adapter *TransactionAdapter, classifier *InstructionClassifier) []pumpfunTradeMatch {
+	var tradeEvents []AnchorEvent
+	for _, event := range GetAnchorEvents(adapter, classifier, p.programID) {
+		if event.Is(pumpfunTradeEventDiscriminator) {
+			tradeEvents = append(tradeEvents, event)
+		}
+	}
+
+	matches := []pumpfunTradeMatch{}
+	used := make([]bool, len(tradeEvents))
+	for _, instruction := range p.getInstructions(classifier, pumpfunBuyDiscriminator, pumpfunSellDiscriminator) {
+		for i, event := range tradeEvents {
+			if used[i] {
+				continue
+			}
+			if event.Instruction != nil && !classifier.IsNestedIn(*event.Instruction, instruction) {
+				continue
+			}
+
+			decoded, err := decodePumpfunTradeEvent(event.Payload())
+			if err != nil {
+				continue
+			}
+			used[i] = true
+
+			if accounts := classifier.GetInstructionAccounts(instruction); len(accounts) > pumpfunTradeBondingCurveIndex {
+				decoded.BondingCurve = accounts[pumpfunTradeBondingCurveIndex]
+			}
+			decoded.IDX = GetIDX(instruction)
+			matches = append(matches, pumpfunTradeMatch{instruction: instruction, event: decoded})
+			break
+		}
+	}
+	return matches
+}
+
+// createEvents 解析 CreateEvent；没有事件时回退到 create 指令参数
+func (p *PumpfunParser) createEvents(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.PumpfunCreateEvent {
+	var createEvents []AnchorEvent
+	for _, event := range GetAnchorEvents(adapter, classifier, p.programID) {
+		if event.Is(pumpfunCreateEventDiscriminator) {
+			createEvents = append(createEvents, event)
+		}
+	}
+
+	events := []model.PumpfunCreateEvent{}
+	for i, instruction := range p.getInstructions(classifier, pumpfunCreateDiscriminator) {
+		var event model.PumpfunCreateEvent
+		var err error
+		if i < len(createEvents) {
+			event, err = decodePumpfunCreateEvent(createEvents[i].Payload())
+		} else {
+			event, err = decodePumpfunCreateInstruction(classifier, instruction)
+		}
+		if err != nil || event.Mint == "" {
+			continue
+		}
+		event.IDX = GetIDX(instruction)
+		events = append(events, event)
+	}
+	return events
+}
+
+// getInstructions 获取数据以任一指令标识开头的 Pump.fun 指令
+func (p *PumpfunParser) getInstructions(classifier *InstructionClassifier, discriminators ...[]byte) []model.ClassifiedInstruction {
+	var result []model.ClassifiedInstruction
+	for _, instruction := range classifier.GetInstructions(p.programID) {
+		data, err := util.Base58Decode(GetInstruction(instruction).Data)
+		if err != nil {
+			continue
+		}
+		for _, discriminator := range discriminators {
+			if bytes.HasPrefix(data, discriminator) {
+				result = append(result, instruction)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// decodePumpfunTradeEvent 解码 TradeEvent：mint, sol_amount, token_amount, is_buy, user, timestamp, virtual reserves[, real reserves]
+func decodePumpfunTradeEvent(data []byte) (model.PumpfunTradeEvent, error) {
+	reader := NewBinaryReader(data)
+	var event model.PumpfunTradeEvent
+	var err error
+
+	if event.Mint, err = reader.ReadPubkey(); err != nil {
+		return event, err
+	}
+	if event.SolAmount, err = reader.ReadU64(); err != nil {
+		return event, err
+	}
+	if event.TokenAmount, err = reader.ReadU64(); err != nil {
+		return event, err
+	}
+	if event.IsBuy, err = reader.ReadBool(); err != nil {
+		return event, err
+	}
+	if event.User, err = reader.ReadPubkey(); err != nil {
+		return event, err
+	}
+	if event.Timestamp, err = reader.ReadI64(); err != nil {
+		return event, err
+	}
+	if event.VirtualSolReserves, err = reader.ReadU64(); err != nil {
+		return event, err
+	}
+	if event.VirtualTokenReserves, err = reader.ReadU64(); err != nil {
+		return event, err
+	}
+
+	// 新版本事件追加真实储备
+	if reader.Remaining() >= 16 {
+		event.RealSolReserves, _ = reader.ReadU64()
+		event.RealTokenReserves, _ = reader.ReadU64()
+	}
+	return event, nil
+}
+
+// decodePumpfunCreateEvent 解码 CreateEvent：name, symbol, uri, mint, bonding_curve, user
+func decodePumpfunCreateEvent(data []byte) (model.PumpfunCreateEvent, error) {
+	reader := NewBinaryReader(data)
+	var event model.PumpfunCreateEvent
+	var err error
+
+	if event.Name, event.Symbol, event.URI, err = readPumpfunMetadata(reader); err != nil {
+		return event, err
+	}
+	if event.Mint, err = reader.ReadPubkey(); err != nil {
+		return event, err
+	}
+	if event.BondingCurve, err = reader.ReadPubkey(); err != nil {
+		return event, err
+	}
+	if event.User, err = reader.ReadPubkey(); err != nil {
+		return event, err
+	}
+	return event, nil
+}
+
+// decodePumpfunCreateInstruction 从 create 指令参数与账户中还原创建信息
+func decodePumpfunCreateInstruction(classifier *InstructionClassifier, instruction model.ClassifiedInstruction) (model.PumpfunCreateEvent, error) {
+	var event model.PumpfunCreateEvent
+	data, err := util.Base58Decode(GetInstruction(instruction).Data)
+	if err != nil {
+		return event, err
+	}
+
+	reader := NewBinaryReader(data)
+	if err := reader.Skip(8); err != nil {
+		return event, err
+	}
+	if event.Name, event.Symbol, event.URI, err = readPumpfunMetadata(reader); err != nil {
+		return event, err
+	}
+
+	// accounts: mint, mint authority, bonding curve, associated bonding curve, global, mpl program, metadata, user, ...
+	accounts := classifier.GetInstructionAccounts(instruction)
+	if len(accounts) > 7 {
+		event.Mint = accounts[0]
+		event.BondingCurve = accounts[pumpfunCreateBondingCurveIndex]
+		event.User = accounts[7]
+	}
+	return event, nil
+}
+
+// readPumpfunMetadata 读取 name、symbol、uri
+func readPumpfunMetadata(reader *BinaryReader) (name, symbol, uri string, err error) {
+	if name, err = reader.ReadString(); err != nil {
+		return
+	}
+	if symbol, err = reader.ReadString(); err != nil {
+		return
+	}
+	uri, err = reader.ReadString()
+	return
+}
@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+const testBondingCurve = "EbmuTfWGJA3vFNG2NqFnqpgfJ5vhoNUj3ynCAJAzPS1e"
+
+func mustBase58Decode(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := util.Base58Decode(s)
+	if err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return data
+}
+
+// encodePumpfunTradeEvent 构造 TradeEvent 数据（含事件标识）
+func encodePumpfunTradeEvent(t *testing.T, solAmount, tokenAmount uint64, isBuy bool) []byte {
+	data := append([]byte{}, pumpfunTradeEventDiscriminator...)
+	data = append(data, mustBase58Decode(t, testMint)...)
+	data = binary.LittleEndian.AppendUint64(data, solAmount)
+	data = binary.LittleEndian.AppendUint64(data, tokenAmount)
+	if isBuy {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	data = append(data, mustBase58Decode(t, testSigner)...)
+	data = binary.LittleEndian.AppendUint64(data, 1700000000)
+	data = binary.LittleEndian.AppendUint64(data, 30_000_000_000)
+	data = binary.LittleEndian.AppendUint64(data, 1_073_000_000_000_000)
+	return data
+}
+
+// newPumpfunTransaction 构造 Pump.fun 买入交易，事件通过自调用内层指令发出
+func newPumpfunTransaction(t *testing.T) *model.TransactionInfo {
+	pumpfun := config.DEX_PROGRAMS["PUMP_FUN"].ID
+
+	tx := &model.TransactionInfo{}
+	tx.Transaction.Signatures = []string{"pumpSignature"}
+	tx.Transaction.Message.AccountKeys = []string{
+		testSigner, "pumpGlobal111111111111111111111111111111111", "pumpFeeRecipient1111111111111111111111111111",
+		testMint, testBondingCurve, pumpfun,
+	}
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		{ProgramIdIndex: 5, Accounts: []int{1, 2, 3, 4, 0}, Data: util.Base58Encode(append(pumpfunBuyDiscriminator, make([]byte, 16)...))},
+	}
+	tx.Meta = &model.TransactionMeta{
+		Fee:          5000,
+		PreBalances:  []uint64{2_000_005_000, 1, 1, 1, 1, 1},
+		PostBalances: []uint64{1_000_000_000, 1, 1, 1, 1, 1},
+		InnerInstructions: []model.InnerInstruction{
+			{Index: 0, Instructions: []model.TransactionInstruction{
+				{ProgramIdIndex: 5, Accounts: []int{4}, StackHeight: intPtr(2),
+					Data: util.Base58Encode(append(append([]byte{}, anchorEventInstructionTag...), encodePumpfunTradeEvent(t, 1_000_000_000, 35_000_000_000, true)...))},
+			}},
+		},
+	}
+	return tx
+}
+
+func TestPumpfunBuyFromSelfCPIEvent(t *testing.T) {
+	result := NewTransactionParser(nil).ParseAll(newPumpfunTransaction(t), 100, 1700000000)
+	if len(result.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(result.Trades))
+	}
+
+	trade := result.Trades[0]
+	if trade.Type != model.TradeTypeBuy || trade.TokenInMint != config.WSOL_ADDRESS || trade.TokenInAmount != "1000000000" {
+		t.Fatalf("unexpected token in: %+v", trade)
+	}
+	if trade.TokenOutMint != testMint || trade.TokenOutAmount != "35000000000" || trade.TokenOutDecimals != PUMPFUN_TOKEN_DECIMALS {
+		t.Fatalf("unexpected token out: %+v", trade)
+	}
+	if trade.PoolAddress != testBondingCurve || trade.IDX != "0" {
+		t.Fatalf("unexpected pool or idx: %+v", trade)
+	}
+
+	events, ok := result.MoreEvents[EVENT_PUMPFUN_TRADE].([]model.PumpfunTradeEvent)
+	if !ok || len(events) != 1 || events[0].VirtualSolReserves != 30_000_000_000 || events[0].User != testSigner {
+		t.Fatalf("unexpected trade events: %+v", result.MoreEvents)
+	}
+}
+
+func TestPumpfunCreateFromLogs(t *testing.T) {
+	tx := newPumpfunTransaction(t)
+	pumpfun := config.DEX_PROGRAMS["PUMP_FUN"].ID
+
+	metadata := func(data []byte, values ...string) []byte {
+		for _, value := range values {
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(value)))
+			data = append(data, value...)
+		}
+		return data
+	}
+	createEvent := metadata(append([]byte{}, pumpfunCreateEventDiscriminator...), "Test Token", "TEST", "https://example.com/test.json")
+	createEvent = append(createEvent, mustBase58Decode(t, testMint)...)
+	createEvent = append(createEvent, mustBase58Decode(t, testBondingCurve)...)
+	createEvent = append(createEvent, mustBase58Decode(t, testSigner)...)
+
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		{ProgramIdIndex: 5, Accounts: []int{3, 1, 4}, Data: util.Base58Encode(metadata(append([]byte{}, pumpfunCreateDiscriminator...), "Test Token", "TEST", "https://example.com/test.json"))},
+	}
+	tx.Meta.InnerInstructions = nil
+	tx.Meta.LogMessages = []string{
+		"Program " + pumpfun + " invoke [1]",
+		"Program log: Instruction: Create",
+		"Program data: " + base64.StdEncoding.EncodeToString(createEvent),
+		"Program " + pumpfun + " success",
+	}
+
+	result := NewTransactionParser(nil).ParseAll(tx, 100, 1700000000)
+	if len(result.Result.Tokens) != 1 {
+		t.Fatalf("expected 1 token, got %d", len(result.Result.Tokens))
+	}
+	token := result.Result.Tokens[0]
+	if token.Mint != testMint || token.Symbol != "TEST" || token.Name != "Test Token" || token.URI != "https://example.com/test.json" || token.Decimals != 6 {
+		t.Fatalf("unexpected token: %+v", token)
+	}
+}
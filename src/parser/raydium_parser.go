@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+// Raydium V4 指令标识（指令数据首字节）
+const (
+	raydiumV4InstructionInitialize2 = 1
+	raydiumV4InstructionDeposit     = 3
+	raydiumV4InstructionWithdraw    = 4
+	raydiumV4InstructionSwapBaseIn  = 9
+	raydiumV4InstructionSwapBaseOut = 11
+)
+
+// Raydium 兑换指令中池地址所在的账户位置
+const (
+	raydiumV4PoolIndex   = 1 // token program, amm, ...
+	raydiumCPMMPoolIndex = 3 // payer, authority, amm config, pool state, ...
+	raydiumCLMMPoolIndex = 2 // payer, amm config, pool state, ...
+)
+
+// Raydium 流动性指令中池地址与代币 mint 所在的账户位置
+const (
+	raydiumV4CreatePoolIndex      = 4 // token program, ata program, system program, rent, amm, ...
+	raydiumV4CreateCoinMintIndex  = 8
+	raydiumV4CreatePcMintIndex    = 9
+	raydiumV4LiquidityPoolIndex   = 1 // token program, amm, ...
+	raydiumCPMMCreatePoolIndex    = 3 // creator, amm config, authority, pool state, token 0 mint, token 1 mint, ...
+	raydiumCPMMCreateMint0Index   = 4
+	raydiumCPMMCreateMint1Index   = 5
+	raydiumCPMMLiquidityPoolIndex = 2 // owner, authority, pool state, ...
+	raydiumCLMMCreatePoolIndex    = 2 // pool creator, amm config, pool state, token mint 0, token mint 1, ...
+	raydiumCLMMCreateMint0Index   = 3
+	raydiumCLMMCreateMint1Index   = 4
+	raydiumCLMMOpenPoolIndex      = 5 // payer, nft owner, nft mint, nft account, metadata, pool state, ...
+	raydiumCLMMIncreasePoolIndex  = 2 // nft owner, nft account, pool state, ...
+	raydiumCLMMDecreasePoolIndex  = 3 // nft owner, nft account, personal position, pool state, ...
+)
+
+func init() {
+	v4 := config.DEX_PROGRAMS["RAYDIUM_V4"].ID
+	RegisterDexParser(v4, NewSwapInstructionParser(v4, raydiumV4PoolIndex,
+		[]byte{raydiumV4InstructionSwapBaseIn},
+		[]byte{raydiumV4InstructionSwapBaseOut},
+	))
+
+	cpmm := config.DEX_PROGRAMS["RAYDIUM_CPMM"].ID
+	RegisterDexParser(cpmm, NewSwapInstructionParser(cpmm, raydiumCPMMPoolIndex,
+		AnchorDiscriminator("swap_base_input"),
+		AnchorDiscriminator("swap_base_output"),
+	))
+
+	clmm := config.DEX_PROGRAMS["RAYDIUM_CL"].ID
+	RegisterDexParser(clmm, NewSwapInstructionParser(clmm, raydiumCLMMPoolIndex,
+		AnchorDiscriminator("swap"),
+		AnchorDiscriminator("swap_v2"),
+	))
+
+	RegisterLiquidityParser(v4, NewLiquidityInstructionParser(v4).
+		WithCreate(raydiumV4CreatePoolIndex, raydiumV4CreateCoinMintIndex, raydiumV4CreatePcMintIndex, []byte{raydiumV4InstructionInitialize2}).
+		With(model.PoolEventTypeAdd, raydiumV4LiquidityPoolIndex, []byte{raydiumV4InstructionDeposit}).
+		With(model.PoolEventTypeRemove, raydiumV4LiquidityPoolIndex, []byte{raydiumV4InstructionWithdraw}))
+
+	RegisterLiquidityParser(cpmm, NewLiquidityInstructionParser(cpmm).
+		WithCreate(raydiumCPMMCreatePoolIndex, raydiumCPMMCreateMint0Index, raydiumCPMMCreateMint1Index, AnchorDiscriminator("initialize")).
+		With(model.PoolEventTypeAdd, raydiumCPMMLiquidityPoolIndex, AnchorDiscriminator("deposit")).
+		With(model.PoolEventTypeRemove, raydiumCPMMLiquidityPoolIndex, AnchorDiscriminator("withdraw")))
+
+	RegisterLiquidityParser(clmm, NewLiquidityInstructionParser(clmm).
+		WithCreate(raydiumCLMMCreatePoolIndex, raydiumCLMMCreateMint0Index, raydiumCLMMCreateMint1Index, AnchorDiscriminator("create_pool")).
+		With(model.PoolEventTypeAdd, raydiumCLMMOpenPoolIndex,
+			AnchorDiscriminator("open_position"),
+			AnchorDiscriminator("open_position_v2"),
+			AnchorDiscriminator("open_position_with_token22_nft")).
+		With(model.PoolEventTypeAdd, raydiumCLMMIncreasePoolIndex,
+			AnchorDiscriminator("increase_liquidity"),
+			AnchorDiscriminator("increase_liquidity_v2")).
+		With(model.PoolEventTypeRemove, raydiumCLMMDecreasePoolIndex,
+			AnchorDiscriminator("decrease_liquidity"),
+			AnchorDiscriminator("decrease_liquidity_v2")))
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/util"
+)
+
+func TestRaydiumCPMMSwap(t *testing.T) {
+	tx := newTestTransaction()
+	tx.Transaction.Message.AccountKeys[2] = config.DEX_PROGRAMS["RAYDIUM_CPMM"].ID
+	// swap_base_input: payer, authority, amm config, pool state, ...
+	tx.Transaction.Message.Instructions[0].Accounts = []int{0, 7, 3, 8, 1, 4}
+	tx.Transaction.Message.Instructions[0].Data = util.Base58Encode(append(AnchorDiscriminator("swap_base_input"), make([]byte, 16)...))
+
+	result := NewTransactionParser(nil).ParseAll(tx, 100, 1700000000)
+	if len(result.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(result.Trades))
+	}
+	if trade := result.Trades[0]; trade.PoolAddress != testPool || trade.AMM != "Raydium CPMM" {
+		t.Fatalf("unexpected trade: %+v", trade)
+	}
+}
+
+func TestRaydiumV4IgnoresNonSwapInstruction(t *testing.T) {
+	tx := newTestTransaction()
+	tx.Transaction.Message.Instructions[0].Data = util.Base58Encode([]byte{1})
+
+	if result := NewTransactionParser(nil).ParseAll(tx, 100, 1700000000); len(result.Trades) != 0 {
+		t.Fatalf("non swap instruction should not produce trades, got %d", len(result.Trades))
+	}
+}
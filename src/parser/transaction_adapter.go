@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"math/big"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+// TokenAccountInfo 代币账户信息（来自交易前后的代币余额）
+type TokenAccountInfo struct {
+	Mint     string
+	Owner    string
+	Decimals uint8
+}
+
+// TransactionAdapter 交易数据适配器，统一访问账户、余额与元数据
+type TransactionAdapter struct {
+	tx            *model.TransactionInfo
+	slot          uint64
+	blockTime     uint64
+	accountKeys   []string
+	decimals      map[string]uint8            // mint -> decimals
+	tokenAccounts map[string]TokenAccountInfo // token account -> mint/owner
+}
+
+// NewTransactionAdapter 创建交易适配器
+func NewTransactionAdapter(tx *model.TransactionInfo, slot, blockTime uint64) *TransactionAdapter {
+	adapter := &TransactionAdapter{
+		tx:            tx,
+		slot:          slot,
+		blockTime:     blockTime,
+		decimals:      make(map[string]uint8),
+		tokenAccounts: make(map[string]TokenAccountInfo),
+	}
+
+	// 完整账户列表：静态账户 + 地址查找表加载的可写账户 + 只读账户
+	keys := tx.Transaction.Message.AccountKeys
+	adapter.accountKeys = make([]string, 0, len(keys))
+	adapter.accountKeys = append(adapter.accountKeys, keys...)
+	if tx.Meta != nil && tx.Meta.LoadedAddresses != nil {
+		adapter.accountKeys = append(adapter.accountKeys, tx.Meta.LoadedAddresses.Writable...)
+		adapter.accountKeys = append(adapter.accountKeys, tx.Meta.LoadedAddresses.Readonly...)
+	}
+
+	if tx.Meta != nil {
+		for _, balances := range [][]model.TokenBalance{tx.Meta.PreTokenBalances, tx.Meta.PostTokenBalances} {
+			for _, balance := range balances {
+				decimals := uint8(balance.UiTokenAmount.Decimals)
+				adapter.decimals[balance.Mint] = decimals
+				adapter.tokenAccounts[adapter.GetAccountKey(balance.AccountIndex)] = TokenAccountInfo{
+					Mint:     balance.Mint,
+					Owner:    balance.Owner,
+					Decimals: decimals,
+				}
+			}
+		}
+	}
+
+	return adapter
+}
+
+// Transaction 返回原始交易
+func (a *TransactionAdapter) Transaction() *model.TransactionInfo {
+	return a.tx
+}
+
+// Meta 返回交易元数据（可能为 nil）
+func (a *TransactionAdapter) Meta() *model.TransactionMeta {
+	return a.tx.Meta
+}
+
+// Signature 返回交易签名
+func (a *TransactionAdapter) Signature() string {
+	if len(a.tx.Transaction.Signatures) == 0 {
+		return ""
+	}
+	return a.tx.Transaction.Signatures[0]
+}
+
+// Signer 返回交易签名者（手续费支付者）
+func (a *TransactionAdapter) Signer() string {
+	return a.GetAccountKey(0)
+}
+
+// Slot 返回交易所在 slot
+func (a *TransactionAdapter) Slot() uint64 {
+	return a.slot
+}
+
+// BlockTime 返回区块时间
+func (a *TransactionAdapter) BlockTime() uint64 {
+	return a.blockTime
+}
+
+// AccountKeys 返回包含查找表地址的完整账户列表
+func (a *TransactionAdapter) AccountKeys() []string {
+	return a.accountKeys
+}
+
+// GetAccountKey 根据索引获取账户地址，越界时返回空字符串
+func (a *TransactionAdapter) GetAccountKey(index int) string {
+	if index < 0 || index >= len(a.accountKeys) {
+		return ""
+	}
+	return a.accountKeys[index]
+}
+
+// GetAccountIndex 根据地址获取账户索引，不存在时返回 -1
+func (a *TransactionAdapter) GetAccountIndex(account string) int {
+	for i, key := range a.accountKeys {
+		if key == account {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetTokenAccountInfo 获取代币账户对应的 mint 与所有者
+func (a *TransactionAdapter) GetTokenAccountInfo(account string) (TokenAccountInfo, bool) {
+	info, ok := a.tokenAccounts[account]
+	return info, ok
+}
+
+// GetPreLamports 获取账户交易前的 lamports 余额
+func (a *TransactionAdapter) GetPreLamports(account string) uint64 {
+	index := a.GetAccountIndex(account)
+	if a.tx.Meta == nil || index < 0 || index >= len(a.tx.Meta.PreBalances) {
+		return 0
+	}
+	return a.tx.Meta.PreBalances[index]
+}
+
+// IsSuccess 交易是否执行成功
+func (a *TransactionAdapter) IsSuccess() bool {
+	return a.tx.Meta != nil && a.tx.Meta.Err == nil
+}
+
+// Fee 返回交易手续费（SOL）
+func (a *TransactionAdapter) Fee() model.TokenAmount {
+	var fee uint64
+	if a.tx.Meta != nil {
+		fee = a.tx.Meta.Fee
+	}
+	return model.TokenAmount{
+		Amount:   new(big.Int).SetUint64(fee).String(),
+		UIAmount: rawToUIAmount(new(big.Int).SetUint64(fee), SOL_DECIMALS),
+		Decimals: SOL_DECIMALS,
+	}
+}
+
+// TokenDecimals 返回代币精度，SOL/WSOL 固定为 9
+func (a *TransactionAdapter) TokenDecimals(mint string) uint8 {
+	if decimals, ok := a.decimals[mint]; ok {
+		return decimals
+	}
+	if isSOLMint(mint) {
+		return SOL_DECIMALS
+	}
+	return 0
+}
@@ -0,0 +1,288 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+// TransactionParser 原生 Go 交易解析器（替代 Deno 解析服务）
+type TransactionParser struct {
+	config model.ParseConfig
+}
+
+// NewTransactionParser 创建交易解析器，config 为 nil 时使用默认配置
+func NewTransactionParser(cfg *model.ParseConfig) *TransactionParser {
+	parser := &TransactionParser{}
+	if cfg != nil {
+		parser.config = *cfg
+	}
+	return parser
+}
+
+// ParseBlock 解析区块内所有交易
+func (p *TransactionParser) ParseBlock(block *model.Block, slot uint64) []model.ParseResult {
+	if block == nil {
+		return nil
+	}
+
+	var blockTime uint64
+	if block.BlockTime != nil {
+		blockTime = uint64(*block.BlockTime)
+	}
+
+	results := make([]model.ParseResult, 0, len(block.Transactions))
+	for i := range block.Transactions {
+		results = append(results, p.ParseAll(&block.Transactions[i], slot, blockTime))
+	}
+	return results
+}
+
+// ParseAll 解析单笔交易：手续费、余额变化、代币转账与交易信息
+func (p *TransactionParser) ParseAll(tx *model.TransactionInfo, slot, blockTime uint64) (result model.ParseResult) {
+	result = model.ParseResult{
+		State:              true,
+		Trades:             []model.TradeInfo{},
+		Liquidities:        []model.PoolEvent{},
+		Transfers:          []model.TransferData{},
+		TokenBalanceChange: map[string]*model.BalanceChange{},
+		MoreEvents:         map[string]interface{}{},
+	}
+
+	if tx == nil || tx.Meta == nil {
+		result.State = false
+		result.Msg = "transaction meta is missing"
+		return result
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if p.config.ThrowError {
+				panic(r)
+			}
+			result.State = false
+			result.Msg = fmt.Sprintf("parse transaction failed: %v", r)
+		}
+	}()
+
+	adapter := NewTransactionAdapter(tx, slot, blockTime)
+	classifier := NewInstructionClassifier(adapter)
+
+	result.Fee = adapter.Fee()
+	balanceChanges := CalculateBalanceChanges(adapter, adapter.Signer())
+	result.SOLBalanceChange = balanceChanges.SOL
+	result.TokenBalanceChange = balanceChanges.Tokens
+
+	if !adapter.IsSuccess() {
+		result.State = false
+		result.Msg = fmt.Sprintf("transaction failed: %v", tx.Meta.Err)
+		return result
+	}
+
+	result.Transfers = ParseTransfers(adapter, classifier)
+
+	tokens := p.parseProtocols(adapter, classifier, &result)
+
+	result.Result = buildEnhancedResult(&result)
+	result.Result.Tokens = tokens
+	return result
+}
+
+// parseProtocols 依次调用各程序的协议解析器，并按 ProgramIDs / IgnoreProgramIDs 过滤
+// 交易与流动性事件写入 result，协议事件合并到 result.MoreEvents，返回新创建的代币
+func (p *TransactionParser) parseProtocols(adapter *TransactionAdapter, classifier *InstructionClassifier, result *model.ParseResult) []model.ResTokenMetadataStruct {
+	trades := []model.TradeInfo{}
+	tokens := []model.ResTokenMetadataStruct{}
+	for _, programID := range classifier.GetAllProgramIDs() {
+		if !p.shouldParseProgram(programID) {
+			continue
+		}
+
+		if liquidityParser, ok := GetLiquidityParser(programID); ok {
+			result.Liquidities = append(result.Liquidities, liquidityParser.ProcessLiquidity(adapter, classifier)...)
+		}
+
+		dexParser, ok := GetDexParser(programID)
+		if !ok {
+			if !p.config.TryUnknownDEX {
+				continue
+			}
+			dexParser = NewTransferDexParser(programID)
+		}
+		trades = append(trades, dexParser.ProcessTrades(adapter, classifier)...)
+
+		if eventParser, ok := dexParser.(EventParser); ok {
+			for name, events := range eventParser.ProcessEvents(adapter, classifier) {
+				result.MoreEvents[name] = events
+			}
+		}
+		if tokenParser, ok := dexParser.(TokenParser); ok {
+			tokens = append(tokens, tokenParser.ProcessTokens(adapter, classifier)...)
+		}
+	}
+
+	result.Trades = nestTrades(classifier, trades)
+	return tokens
+}
+
+// shouldParseProgram 判断程序是否需要解析
+func (p *TransactionParser) shouldParseProgram(programID string) bool {
+	if config.IsSystemProgram(programID) {
+		return false
+	}
+	for _, ignored := range p.config.IgnoreProgramIDs {
+		if ignored == programID {
+			return false
+		}
+	}
+	if len(p.config.ProgramIDs) == 0 {
+		return true
+	}
+	for _, allowed := range p.config.ProgramIDs {
+		if allowed == programID {
+			return true
+		}
+	}
+	return false
+}
+
+// nestTrades 将嵌套在其他交易指令内部的交易挂到最外层交易的 Hops 上
+// 路由程序（如 Jupiter）与底层 AMM 各自产出交易时，顶层只保留用户级交易，避免重复计量
+// 转账推断的交易（交易机器人、路由等）不作为解码交易的父交易，包含解码交易时直接丢弃
+func nestTrades(classifier *InstructionClassifier, trades []model.TradeInfo) []model.TradeInfo {
+	if len(trades) < 2 {
+		return trades
+	}
+
+	instructions := make([]model.ClassifiedInstruction, len(trades))
+	found := make([]bool, len(trades))
+	for i, trade := range trades {
+		instructions[i], found[i] = classifier.GetInstructionByIDX(trade.IDX)
+	}
+
+	kept := make([]int, 0, len(trades))
+	for j, trade := range trades {
+		if !found[j] || isDecodedTrade(trade) || !containsDecodedTrade(classifier, trades, instructions, found, j) {
+			kept = append(kept, j)
+		}
+	}
+	if len(kept) < len(trades) {
+		filtered := make([]model.TradeInfo, len(kept))
+		for i, j := range kept {
+			filtered[i] = trades[j]
+		}
+		return nestTrades(classifier, filtered)
+	}
+
+	// parent[i] 为包含交易 i 的最外层交易，-1 表示 i 本身位于顶层
+	parent := make([]int, len(trades))
+	for i := range trades {
+		parent[i] = -1
+		if !found[i] {
+			continue
+		}
+		for j := range trades {
+			if i == j || !found[j] || !classifier.IsNestedIn(instructions[i], instructions[j]) {
+				continue
+			}
+			if parent[i] == -1 || classifier.IsNestedIn(instructions[parent[i]], instructions[j]) {
+				parent[i] = j
+			}
+		}
+	}
+
+	order := make([]int, len(trades))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return instructionBefore(instructions[order[a]], instructions[order[b]])
+	})
+
+	hops := make(map[int][]model.TradeInfo)
+	for _, i := range order {
+		if parent[i] != -1 {
+			hops[parent[i]] = append(hops[parent[i]], trades[i])
+		}
+	}
+
+	result := make([]model.TradeInfo, 0, len(trades))
+	for _, i := range order {
+		if parent[i] != -1 {
+			continue
+		}
+		trade := trades[i]
+		if nested := hops[i]; len(nested) > 0 {
+			trade.Hops = append(trade.Hops, nested...)
+		}
+		result = append(result, trade)
+	}
+	return result
+}
+
+// containsDecodedTrade 判断交易 j 的指令内部是否嵌套了专用解析器解码的交易
+func containsDecodedTrade(classifier *InstructionClassifier, trades []model.TradeInfo, instructions []model.ClassifiedInstruction, found []bool, j int) bool {
+	for i, trade := range trades {
+		if i != j && found[i] && isDecodedTrade(trade) && classifier.IsNestedIn(instructions[i], instructions[j]) {
+			return true
+		}
+	}
+	return false
+}
+
+// instructionBefore 判断指令 a 是否先于 b 执行
+func instructionBefore(a, b model.ClassifiedInstruction) bool {
+	if a.OuterIndex != b.OuterIndex {
+		return a.OuterIndex < b.OuterIndex
+	}
+	if a.InnerIndex == nil || b.InnerIndex == nil {
+		return a.InnerIndex == nil && b.InnerIndex != nil
+	}
+	return *a.InnerIndex < *b.InnerIndex
+}
+
+// buildEnhancedResult 将解析结果转换为标准化输出结构
+func buildEnhancedResult(result *model.ParseResult) model.EnhancedResult {
+	enhanced := model.EnhancedResult{
+		Trades:      make([]model.ResSwapStruct, 0, len(result.Trades)),
+		Liquidities: make([]model.ResLpInfoStruct, 0, len(result.Liquidities)),
+	}
+
+	for _, trade := range result.Trades {
+		enhanced.Trades = append(enhanced.Trades, model.ResSwapStruct{
+			TransactionSignature: trade.Signature,
+			BlockTime:            trade.BlockTime,
+			UserAddress:          trade.Signer,
+			TokenInMint:          trade.TokenInMint,
+			TokenInSymbol:        trade.TokenInSymbol,
+			TokenInAmount:        RawAmountToUI(trade.TokenInAmount, trade.TokenInDecimals),
+			TokenOutMint:         trade.TokenOutMint,
+			TokenOutSymbol:       trade.TokenOutSymbol,
+			TokenOutAmount:       RawAmountToUI(trade.TokenOutAmount, trade.TokenOutDecimals),
+			PoolAddress:          trade.PoolAddress,
+			AMM:                  trade.AMM,
+			Route:                trade.Route,
+			SlotNumber:           trade.SlotNumber,
+		})
+	}
+
+	for _, event := range result.Liquidities {
+		enhanced.Liquidities = append(enhanced.Liquidities, model.ResLpInfoStruct{
+			Signature:    event.Signature,
+			Type:         event.Type,
+			Signer:       event.Signer,
+			PoolAddress:  event.PoolAddress,
+			SlotNumber:   event.SlotNumber,
+			BlockTime:    event.BlockTime,
+			AMM:          event.AMM,
+			Token0Mint:   event.Token0Mint,
+			Token0Amount: RawAmountToUI(event.Token0Amount, event.Token0Decimals),
+			Token1Mint:   event.Token1Mint,
+			Token1Amount: RawAmountToUI(event.Token1Amount, event.Token1Decimals),
+		})
+	}
+
+	return enhanced
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+const (
+	testSigner = "5TLRz619uQoDEPtyUK2z4NLVMQF6xrV9hYPRFMXqbNRV"
+	testMint   = "F3QEA7LhaUmVVcPTdRCi62hAYd8bLwPbNgwbwPh6SE4A"
+	testPool   = "58oQChx4yWmvKdwLLZzBi4ChoCc2fqCUWBkwMihLYQo2"
+)
+
+// newTestTransaction 构造签名者通过 Raydium V4 用 1 SOL 买入 1000 个代币的交易
+func newTestTransaction() *model.TransactionInfo {
+	tx := &model.TransactionInfo{}
+	tx.Transaction.Signatures = []string{"testSignature"}
+	tx.Transaction.Message.AccountKeys = []string{
+		testSigner,
+		"userTokenAccount1111111111111111111111111111",
+		config.DEX_PROGRAMS["RAYDIUM_V4"].ID,
+		"poolTokenAccount1111111111111111111111111111",
+		"poolSolVault1111111111111111111111111111111",
+		config.SYSTEM_PROGRAM_ID,
+		config.TOKEN_PROGRAM_ID,
+		"raydiumAuthority111111111111111111111111111",
+		testPool,
+	}
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		// swapBaseIn: token program, amm, amm authority, ...
+		{ProgramIdIndex: 2, Accounts: []int{6, 8, 7, 4, 3, 1, 0}, Data: encodeTokenInstruction(raydiumV4InstructionSwapBaseIn, 1_000_000_000, make([]byte, 8)...)},
+	}
+
+	solTransfer := make([]byte, 12)
+	binary.LittleEndian.PutUint32(solTransfer, systemInstructionTransfer)
+	binary.LittleEndian.PutUint64(solTransfer[4:], 1_000_000_000)
+
+	tx.Meta = &model.TransactionMeta{
+		Fee:          5000,
+		PreBalances:  []uint64{2_000_005_000, 2039280, 1, 2039280, 5_000_000_000, 1, 1, 0, 6124800},
+		PostBalances: []uint64{1_000_000_000, 2039280, 1, 2039280, 6_000_000_000, 1, 1, 0, 6124800},
+		InnerInstructions: []model.InnerInstruction{
+			{Index: 0, Instructions: []model.TransactionInstruction{
+				// system transfer: from, to
+				{ProgramIdIndex: 5, Accounts: []int{0, 4}, Data: util.Base58Encode(solTransfer)},
+				// token transfer: source, destination, owner
+				{ProgramIdIndex: 6, Accounts: []int{3, 1, 7}, Data: encodeTokenInstruction(tokenInstructionTransfer, 1_000_000_000)},
+			}},
+		},
+		PreTokenBalances: []model.TokenBalance{
+			{AccountIndex: 1, Mint: testMint, Owner: testSigner, UiTokenAmount: model.UiTokenAmount{Amount: "0", Decimals: 6}},
+			{AccountIndex: 3, Mint: testMint, Owner: "raydiumAuthority111111111111111111111111111", UiTokenAmount: model.UiTokenAmount{Amount: "5000000000", Decimals: 6}},
+		},
+		PostTokenBalances: []model.TokenBalance{
+			{AccountIndex: 1, Mint: testMint, Owner: testSigner, UiTokenAmount: model.UiTokenAmount{Amount: "1000000000", Decimals: 6}},
+			{AccountIndex: 3, Mint: testMint, Owner: "raydiumAuthority111111111111111111111111111", UiTokenAmount: model.UiTokenAmount{Amount: "4000000000", Decimals: 6}},
+		},
+	}
+	return tx
+}
+
+func TestTransactionParser_ParseAll(t *testing.T) {
+	result := NewTransactionParser(nil).ParseAll(newTestTransaction(), 100, 1700000000)
+	if !result.State {
+		t.Fatalf("parse failed: %s", result.Msg)
+	}
+
+	if result.Fee.Amount != "5000" {
+		t.Fatalf("unexpected fee: %s", result.Fee.Amount)
+	}
+
+	if len(result.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(result.Trades))
+	}
+
+	trade := result.Trades[0]
+	if trade.Type != model.TradeTypeBuy {
+		t.Fatalf("expected BUY, got %s", trade.Type)
+	}
+	if trade.TokenInMint != config.WSOL_ADDRESS || trade.TokenInAmount != "1000000000" {
+		t.Fatalf("unexpected token in: %s %s", trade.TokenInMint, trade.TokenInAmount)
+	}
+	if trade.TokenOutMint != testMint || trade.TokenOutAmount != "1000000000" {
+		t.Fatalf("unexpected token out: %s %s", trade.TokenOutMint, trade.TokenOutAmount)
+	}
+
+	if trade.PoolAddress != testPool || result.Result.Trades[0].PoolAddress != testPool {
+		t.Fatalf("unexpected pool address: %s", trade.PoolAddress)
+	}
+
+	if got := result.Result.Trades[0].TokenOutAmount; got != 1000 {
+		t.Fatalf("unexpected ui amount: %v", got)
+	}
+}
+
+func TestTransactionParser_FailedTransaction(t *testing.T) {
+	tx := newTestTransaction()
+	tx.Meta.Err = map[string]interface{}{"InstructionError": []interface{}{0, "Custom"}}
+
+	result := NewTransactionParser(nil).ParseAll(tx, 100, 1700000000)
+	if result.State {
+		t.Fatalf("expected failed state")
+	}
+	if len(result.Trades) != 0 {
+		t.Fatalf("failed transaction should not produce trades")
+	}
+}
+
+func TestTransactionParser_ProgramFilters(t *testing.T) {
+	raydium := config.DEX_PROGRAMS["RAYDIUM_V4"].ID
+
+	ignored := NewTransactionParser(&model.ParseConfig{IgnoreProgramIDs: []string{raydium}}).ParseAll(newTestTransaction(), 100, 1700000000)
+	if len(ignored.Trades) != 0 {
+		t.Fatalf("ignored program should not produce trades")
+	}
+
+	other := NewTransactionParser(&model.ParseConfig{ProgramIDs: []string{config.DEX_PROGRAMS["ORCA"].ID}}).ParseAll(newTestTransaction(), 100, 1700000000)
+	if len(other.Trades) != 0 {
+		t.Fatalf("programs outside ProgramIDs should not produce trades")
+	}
+}
+
+func TestTransactionParser_TryUnknownDEX(t *testing.T) {
+	tx := newTestTransaction()
+	tx.Transaction.Message.AccountKeys[2] = "unknownDex111111111111111111111111111111111"
+
+	if result := NewTransactionParser(nil).ParseAll(tx, 100, 1700000000); len(result.Trades) != 0 {
+		t.Fatalf("unknown program should be skipped by default")
+	}
+
+	result := NewTransactionParser(&model.ParseConfig{TryUnknownDEX: true}).ParseAll(tx, 100, 1700000000)
+	if len(result.Trades) != 1 {
+		t.Fatalf("expected 1 inferred trade, got %d", len(result.Trades))
+	}
+	if trade := result.Trades[0]; trade.ProgramID != "unknownDex111111111111111111111111111111111" || trade.IDX != "0" {
+		t.Fatalf("unexpected inferred trade: %+v", trade)
+	}
+}
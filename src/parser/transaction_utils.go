@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/go-solana-parse/src/config"
+)
+
+// SOL_DECIMALS SOL 精度
+const SOL_DECIMALS uint8 = 9
+
+// isSOLMint 判断是否为 SOL 或 WSOL
+func isSOLMint(mint string) bool {
+	return mint == config.SOL_ADDRESS || mint == config.WSOL_ADDRESS
+}
+
+// parseRawAmount 解析原始整数数量字符串，失败时返回 0
+func parseRawAmount(amount string) *big.Int {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return value
+}
+
+// rawToUIAmount 按精度将原始数量换算为 UI 数量
+func rawToUIAmount(amount *big.Int, decimals uint8) float64 {
+	if amount == nil {
+		return 0
+	}
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), scale).Float64()
+	return value
+}
+
+// RawAmountToUI 将原始数量字符串换算为 UI 数量
+func RawAmountToUI(amount string, decimals uint8) float64 {
+	return rawToUIAmount(parseRawAmount(amount), decimals)
+}
+
+// getTokenSymbol 获取代币符号，未知代币返回空字符串
+func getTokenSymbol(mint string) string {
+	if symbol, ok := config.SOLANA_DEX_ADDRESS_TO_NAME[mint]; ok {
+		return symbol
+	}
+	return ""
+}
+
+// formatIDX 格式化指令位置标识
+func formatIDX(outerIndex int, innerIndex *int) string {
+	if innerIndex == nil {
+		return fmt.Sprintf("%d", outerIndex)
+	}
+	return fmt.Sprintf("%d-%d", outerIndex, *innerIndex)
+}
+
+// AnchorDiscriminator 计算 Anchor 指令标识：sha256("global:<name>") 的前 8 字节
+func AnchorDiscriminator(name string) []byte {
+	hash := sha256.Sum256([]byte("global:" + name))
+	return hash[:8]
+}
+
+// AnchorEventDiscriminator 计算 Anchor 事件标识：sha256("event:<Name>") 的前 8 字节
+func AnchorEventDiscriminator(name string) []byte {
+	hash := sha256.Sum256([]byte("event:" + name))
+	return hash[:8]
+}
@@ -0,0 +1,256 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// SPL Token 指令类型
+const (
+	TRANSFER_TYPE_TRANSFER                  = "transfer"
+	TRANSFER_TYPE_TRANSFER_CHECKED          = "transferChecked"
+	TRANSFER_TYPE_MINT_TO                   = "mintTo"
+	TRANSFER_TYPE_BURN                      = "burn"
+	TRANSFER_TYPE_CLOSE_ACCOUNT             = "closeAccount"
+	TRANSFER_TYPE_TRANSFER_CHECKED_WITH_FEE = "transferCheckedWithFee"
+)
+
+// SPL Token 指令标识（指令数据首字节）
+const (
+	tokenInstructionTransfer             = 3
+	tokenInstructionMintTo               = 7
+	tokenInstructionBurn                 = 8
+	tokenInstructionCloseAccount         = 9
+	tokenInstructionTransferChecked      = 12
+	tokenInstructionMintToChecked        = 14
+	tokenInstructionBurnChecked          = 15
+	tokenInstructionTransferFeeExtension = 26
+
+	transferFeeInstructionTransferCheckedWithFee = 1
+
+	systemInstructionTransfer = 2
+)
+
+// isTokenProgram 判断是否为 SPL Token 或 Token-2022 程序
+func isTokenProgram(programID string) bool {
+	return programID == config.TOKEN_PROGRAM_ID || programID == config.TOKEN_2022_PROGRAM_ID
+}
+
+// ParseTransfers 解析交易中所有 SPL Token / Token-2022 转账类指令
+func ParseTransfers(adapter *TransactionAdapter, classifier *InstructionClassifier) []model.TransferData {
+	transfers := []model.TransferData{}
+	for _, instruction := range classifier.GetAllInstructions() {
+		if !isTokenProgram(instruction.ProgramID) {
+			continue
+		}
+		if transfer, ok := DecodeTokenTransfer(adapter, classifier, instruction); ok {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers
+}
+
+// GetTransfersOf 获取某条指令内层 CPI 中的代币转账与 SOL 转账
+func GetTransfersOf(adapter *TransactionAdapter, classifier *InstructionClassifier, parent model.ClassifiedInstruction) []model.TransferData {
+	transfers := []model.TransferData{}
+	for _, instruction := range classifier.GetInnerInstructions(parent) {
+		var transfer model.TransferData
+		var ok bool
+		switch {
+		case isTokenProgram(instruction.ProgramID):
+			transfer, ok = DecodeTokenTransfer(adapter, classifier, instruction)
+		case instruction.ProgramID == config.SYSTEM_PROGRAM_ID:
+			transfer, ok = DecodeSystemTransfer(classifier, instruction)
+		}
+		if ok {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers
+}
+
+// DecodeSystemTransfer 解码系统程序 SOL 转账指令，非转账指令返回 false
+func DecodeSystemTransfer(classifier *InstructionClassifier, classified model.ClassifiedInstruction) (model.TransferData, bool) {
+	data, err := util.Base58Decode(GetInstruction(classified).Data)
+	if err != nil {
+		return model.TransferData{}, false
+	}
+
+	// 系统指令以 u32 作为指令标识；accounts: from, to
+	reader := NewBinaryReader(data)
+	tag, err := reader.ReadU32()
+	if err != nil || tag != systemInstructionTransfer {
+		return model.TransferData{}, false
+	}
+	lamports, err := reader.ReadU64()
+	accounts := classifier.GetInstructionAccounts(classified)
+	if err != nil || len(accounts) < 2 {
+		return model.TransferData{}, false
+	}
+
+	innerIndex := -1
+	if classified.InnerIndex != nil {
+		innerIndex = *classified.InnerIndex
+	}
+
+	return model.TransferData{
+		Type:             TRANSFER_TYPE_TRANSFER,
+		ProgramID:        classified.ProgramID,
+		Index:            classified.OuterIndex,
+		InstructionIndex: innerIndex,
+		Info: model.TransferInfo{
+			Source:           accounts[0],
+			Destination:      accounts[1],
+			Owner:            accounts[0],
+			SourceOwner:      accounts[0],
+			DestinationOwner: accounts[1],
+			Mint:             config.SOL_ADDRESS,
+			Amount:           formatU64(lamports),
+			Decimals:         SOL_DECIMALS,
+		},
+	}, true
+}
+
+// DecodeTokenTransfer 解码单条代币指令，非转账类指令返回 false
+func DecodeTokenTransfer(adapter *TransactionAdapter, classifier *InstructionClassifier, classified model.ClassifiedInstruction) (model.TransferData, bool) {
+	data, err := util.Base58Decode(GetInstruction(classified).Data)
+	if err != nil || len(data) == 0 {
+		return model.TransferData{}, false
+	}
+
+	accounts := classifier.GetInstructionAccounts(classified)
+	reader := NewBinaryReader(data[1:])
+
+	var transferType string
+	var info model.TransferInfo
+
+	switch data[0] {
+	case tokenInstructionTransfer:
+		// accounts: source, destination, owner
+		amount, err := reader.ReadU64()
+		if err != nil || len(accounts) < 3 {
+			return model.TransferData{}, false
+		}
+		transferType = TRANSFER_TYPE_TRANSFER
+		info = model.TransferInfo{Source: accounts[0], Destination: accounts[1], Owner: accounts[2], Amount: formatU64(amount)}
+
+	case tokenInstructionTransferChecked:
+		// accounts: source, mint, destination, owner
+		amount, err := reader.ReadU64()
+		if err != nil || len(accounts) < 4 {
+			return model.TransferData{}, false
+		}
+		decimals, _ := reader.ReadU8()
+		transferType = TRANSFER_TYPE_TRANSFER_CHECKED
+		info = model.TransferInfo{Source: accounts[0], Mint: accounts[1], Destination: accounts[2], Owner: accounts[3], Amount: formatU64(amount), Decimals: decimals}
+
+	case tokenInstructionMintTo, tokenInstructionMintToChecked:
+		// accounts: mint, destination, authority
+		amount, err := reader.ReadU64()
+		if err != nil || len(accounts) < 3 {
+			return model.TransferData{}, false
+		}
+		transferType = TRANSFER_TYPE_MINT_TO
+		info = model.TransferInfo{Mint: accounts[0], Destination: accounts[1], Owner: accounts[2], Amount: formatU64(amount)}
+		if data[0] == tokenInstructionMintToChecked {
+			info.Decimals, _ = reader.ReadU8()
+		}
+
+	case tokenInstructionBurn, tokenInstructionBurnChecked:
+		// accounts: account, mint, owner
+		amount, err := reader.ReadU64()
+		if err != nil || len(accounts) < 3 {
+			return model.TransferData{}, false
+		}
+		transferType = TRANSFER_TYPE_BURN
+		info = model.TransferInfo{Source: accounts[0], Mint: accounts[1], Owner: accounts[2], Amount: formatU64(amount)}
+		if data[0] == tokenInstructionBurnChecked {
+			info.Decimals, _ = reader.ReadU8()
+		}
+
+	case tokenInstructionCloseAccount:
+		// accounts: account, destination, owner；关闭账户转出的是账户内全部 lamports
+		if len(accounts) < 3 {
+			return model.TransferData{}, false
+		}
+		transferType = TRANSFER_TYPE_CLOSE_ACCOUNT
+		info = model.TransferInfo{
+			Source:      accounts[0],
+			Destination: accounts[1],
+			Owner:       accounts[2],
+			Mint:        config.SOL_ADDRESS,
+			Amount:      formatU64(adapter.GetPreLamports(accounts[0])),
+			Decimals:    SOL_DECIMALS,
+		}
+
+	case tokenInstructionTransferFeeExtension:
+		// Token-2022 TransferCheckedWithFee；accounts: source, mint, destination, authority
+		subInstruction, err := reader.ReadU8()
+		if err != nil || subInstruction != transferFeeInstructionTransferCheckedWithFee || len(accounts) < 4 {
+			return model.TransferData{}, false
+		}
+		amount, err := reader.ReadU64()
+		if err != nil {
+			return model.TransferData{}, false
+		}
+		decimals, _ := reader.ReadU8()
+		fee, _ := reader.ReadU64()
+		transferType = TRANSFER_TYPE_TRANSFER_CHECKED_WITH_FEE
+		info = model.TransferInfo{Source: accounts[0], Mint: accounts[1], Destination: accounts[2], Owner: accounts[3], Amount: formatU64(amount), Fee: formatU64(fee), Decimals: decimals}
+
+	default:
+		return model.TransferData{}, false
+	}
+
+	fillTransferAccounts(adapter, &info, transferType)
+
+	innerIndex := -1
+	if classified.InnerIndex != nil {
+		innerIndex = *classified.InnerIndex
+	}
+
+	return model.TransferData{
+		Type:             transferType,
+		ProgramID:        classified.ProgramID,
+		Index:            classified.OuterIndex,
+		InstructionIndex: innerIndex,
+		Info:             info,
+	}, true
+}
+
+// fillTransferAccounts 通过交易前后代币余额补全 mint、精度与账户所有者
+func fillTransferAccounts(adapter *TransactionAdapter, info *model.TransferInfo, transferType string) {
+	if source, ok := adapter.GetTokenAccountInfo(info.Source); ok {
+		info.SourceOwner = source.Owner
+		if info.Mint == "" {
+			info.Mint = source.Mint
+		}
+	}
+	if destination, ok := adapter.GetTokenAccountInfo(info.Destination); ok {
+		info.DestinationOwner = destination.Owner
+		if info.Mint == "" {
+			info.Mint = destination.Mint
+		}
+	}
+
+	// 关闭账户转出的是 SOL，目标为普通钱包
+	if transferType == TRANSFER_TYPE_CLOSE_ACCOUNT {
+		info.DestinationOwner = info.Destination
+		return
+	}
+
+	if info.SourceOwner == "" && transferType != TRANSFER_TYPE_MINT_TO {
+		info.SourceOwner = info.Owner
+	}
+	if info.Decimals == 0 && info.Mint != "" {
+		info.Decimals = adapter.TokenDecimals(info.Mint)
+	}
+}
+
+// formatU64 格式化 u64 数量
+func formatU64(value uint64) string {
+	return strconv.FormatUint(value, 10)
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// encodeTokenInstruction 构造 SPL Token 指令数据：tag + u64 amount + 可选 decimals
+func encodeTokenInstruction(tag byte, amount uint64, extra ...byte) string {
+	data := make([]byte, 9)
+	data[0] = tag
+	binary.LittleEndian.PutUint64(data[1:], amount)
+	return util.Base58Encode(append(data, extra...))
+}
+
+func TestParseTransfers(t *testing.T) {
+	const (
+		userTokenAccount = "userTokenAccount1111111111111111111111111111"
+		poolTokenAccount = "poolTokenAccount1111111111111111111111111111"
+		poolAuthority    = "poolAuthority111111111111111111111111111111"
+		wsolAccount      = "wsolAccount11111111111111111111111111111111"
+	)
+
+	tx := &model.TransactionInfo{}
+	tx.Transaction.Message.AccountKeys = []string{
+		testSigner, userTokenAccount, poolTokenAccount, testMint, config.TOKEN_PROGRAM_ID, wsolAccount, poolAuthority,
+	}
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		// transferChecked: source, mint, destination, owner
+		{ProgramIdIndex: 4, Accounts: []int{2, 3, 1, 6}, Data: encodeTokenInstruction(tokenInstructionTransferChecked, 1500000, 6)},
+		// closeAccount: account, destination, owner
+		{ProgramIdIndex: 4, Accounts: []int{5, 0, 0}, Data: util.Base58Encode([]byte{tokenInstructionCloseAccount})},
+	}
+	tx.Meta = &model.TransactionMeta{
+		PreBalances:  []uint64{1000000000, 2039280, 2039280, 0, 1, 502039280, 0},
+		PostBalances: []uint64{1502039280, 2039280, 2039280, 0, 1, 0, 0},
+		PostTokenBalances: []model.TokenBalance{
+			{AccountIndex: 1, Mint: testMint, Owner: testSigner, UiTokenAmount: model.UiTokenAmount{Amount: "1500000", Decimals: 6}},
+			{AccountIndex: 2, Mint: testMint, Owner: poolAuthority, UiTokenAmount: model.UiTokenAmount{Amount: "0", Decimals: 6}},
+		},
+	}
+
+	adapter := NewTransactionAdapter(tx, 1, 1)
+	transfers := ParseTransfers(adapter, NewInstructionClassifier(adapter))
+	if len(transfers) != 2 {
+		t.Fatalf("expected 2 transfers, got %d", len(transfers))
+	}
+
+	checked := transfers[0]
+	if checked.Type != TRANSFER_TYPE_TRANSFER_CHECKED || checked.Info.Amount != "1500000" || checked.Info.Decimals != 6 {
+		t.Fatalf("unexpected transferChecked: %+v", checked)
+	}
+	if checked.Info.SourceOwner != poolAuthority || checked.Info.DestinationOwner != testSigner || checked.Info.Mint != testMint {
+		t.Fatalf("unexpected transferChecked accounts: %+v", checked.Info)
+	}
+	if checked.Index != 0 || checked.InstructionIndex != -1 {
+		t.Fatalf("unexpected transfer position: %d/%d", checked.Index, checked.InstructionIndex)
+	}
+
+	closed := transfers[1]
+	if closed.Type != TRANSFER_TYPE_CLOSE_ACCOUNT || closed.Info.Amount != "502039280" || closed.Info.Mint != config.SOL_ADDRESS {
+		t.Fatalf("unexpected closeAccount: %+v", closed)
+	}
+}
+
+func TestBase58RoundTrip(t *testing.T) {
+	decoded, err := util.Base58Decode(config.TOKEN_PROGRAM_ID)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(decoded) != 32 {
+		t.Fatalf("expected 32 bytes, got %d", len(decoded))
+	}
+	if encoded := util.Base58Encode(decoded); encoded != config.TOKEN_PROGRAM_ID {
+		t.Fatalf("round trip mismatch: %s", encoded)
+	}
+}
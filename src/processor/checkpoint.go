@@ -0,0 +1,221 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SlotRange 区块范围 [Start, End)
+type SlotRange struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+}
+
+// Checkpoint 扫描进度：已完成的区块范围与失败区块
+type Checkpoint struct {
+	StartSlot       uint64      `json:"start_slot"`
+	EndSlot         uint64      `json:"end_slot"`
+	CompletedRanges []SlotRange `json:"completed_ranges"`
+	FailedSlots     []uint64    `json:"failed_slots"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// FileCheckpointStore 基于本地 JSON 文件的扫描进度存储
+// 每完成一个 cycle 立即落盘（先写临时文件再重命名），进程中断后重启可跳过已完成范围
+type FileCheckpointStore struct {
+	mu         sync.Mutex
+	path       string
+	checkpoint Checkpoint
+	failedSet  map[uint64]struct{}
+}
+
+// CheckpointFileName 生成扫描范围对应的进度文件名
+func CheckpointFileName(startSlot, endSlot uint64) string {
+	return fmt.Sprintf("checkpoint_%d_%d.json", startSlot, endSlot)
+}
+
+// NewFileCheckpointStore 创建进度存储，文件已存在时加载之前的进度
+func NewFileCheckpointStore(path string, startSlot, endSlot uint64) (*FileCheckpointStore, error) {
+	store := &FileCheckpointStore{
+		path: path,
+		checkpoint: Checkpoint{
+			StartSlot: startSlot,
+			EndSlot:   endSlot,
+		},
+		failedSet: make(map[uint64]struct{}),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取进度文件失败: %v", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("解析进度文件失败: %v", err)
+	}
+	if checkpoint.StartSlot != startSlot || checkpoint.EndSlot != endSlot {
+		return nil, fmt.Errorf("进度文件区块范围 %d - %d 与当前范围 %d - %d 不一致",
+			checkpoint.StartSlot, checkpoint.EndSlot, startSlot, endSlot)
+	}
+
+	store.checkpoint = checkpoint
+	for _, slot := range checkpoint.FailedSlots {
+		store.failedSet[slot] = struct{}{}
+	}
+	return store, nil
+}
+
+// IsCompleted 判断区块范围 [start, end) 是否已全部完成
+func (s *FileCheckpointStore) IsCompleted(start, end uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.checkpoint.CompletedRanges {
+		if r.Start <= start && end <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkCompleted 记录区块范围 [start, end) 已处理完成及其中失败的区块，并立即落盘
+func (s *FileCheckpointStore) MarkCompleted(start, end uint64, failedSlots []uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoint.CompletedRanges = mergeSlotRanges(append(s.checkpoint.CompletedRanges, SlotRange{Start: start, End: end}))
+	for _, slot := range failedSlots {
+		if _, exists := s.failedSet[slot]; !exists {
+			s.failedSet[slot] = struct{}{}
+			s.checkpoint.FailedSlots = append(s.checkpoint.FailedSlots, slot)
+		}
+	}
+	return s.save()
+}
+
+// LoadCheckpointStores 加载目录下全部扫描进度文件（含 replay_ 前缀的），用于重试成功后更新失败列表
+func LoadCheckpointStores(dir string) ([]*FileCheckpointStore, error) {
+	var paths []string
+	for _, pattern := range []string{"checkpoint_*.json", "replay_checkpoint_*.json"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("查找进度文件失败: %v", err)
+		}
+		paths = append(paths, matches...)
+	}
+
+	stores := make([]*FileCheckpointStore, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取进度文件失败: %v", err)
+		}
+		var checkpoint Checkpoint
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return nil, fmt.Errorf("解析进度文件 %s 失败: %v", path, err)
+		}
+		store, err := NewFileCheckpointStore(path, checkpoint.StartSlot, checkpoint.EndSlot)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+	return stores, nil
+}
+
+// ResolveFailedSlots 从失败列表中移除已重试成功的区块，有变化时立即落盘
+func (s *FileCheckpointStore) ResolveFailedSlots(slots []uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resolved := 0
+	for _, slot := range slots {
+		if _, exists := s.failedSet[slot]; exists {
+			delete(s.failedSet, slot)
+			resolved++
+		}
+	}
+	if resolved == 0 {
+		return nil
+	}
+	remaining := s.checkpoint.FailedSlots[:0]
+	for _, slot := range s.checkpoint.FailedSlots {
+		if _, exists := s.failedSet[slot]; exists {
+			remaining = append(remaining, slot)
+		}
+	}
+	s.checkpoint.FailedSlots = remaining
+	return s.save()
+}
+
+// FailedSlots 返回累计的失败区块（含之前运行记录的）
+func (s *FileCheckpointStore) FailedSlots() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]uint64(nil), s.checkpoint.FailedSlots...)
+}
+
+// CompletedSlots 返回已完成的区块数
+func (s *FileCheckpointStore) CompletedSlots() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total uint64
+	for _, r := range s.checkpoint.CompletedRanges {
+		total += r.End - r.Start
+	}
+	return total
+}
+
+// save 写入临时文件后重命名，避免写到一半中断导致进度文件损坏
+func (s *FileCheckpointStore) save() error {
+	s.checkpoint.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(s.checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化进度失败: %v", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("写入进度文件失败: %v", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("保存进度文件失败: %v", err)
+	}
+	return nil
+}
+
+// mergeSlotRanges 排序并合并相交或相邻的区块范围
+func mergeSlotRanges(ranges []SlotRange) []SlotRange {
+	if len(ranges) < 2 {
+		return ranges
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+
+	merged := []SlotRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
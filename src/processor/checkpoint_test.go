@@ -0,0 +1,105 @@
+package processor
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileCheckpointStoreResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CheckpointFileName(1000, 1300))
+
+	store, err := NewFileCheckpointStore(path, 1000, 1300)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	if err := store.MarkCompleted(1200, 1300, []uint64{1234}); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+	if err := store.MarkCompleted(1100, 1200, []uint64{1150, 1234}); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+
+	// 模拟进程重启后重新加载
+	resumed, err := NewFileCheckpointStore(path, 1000, 1300)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	if !resumed.IsCompleted(1100, 1300) || !resumed.IsCompleted(1150, 1250) {
+		t.Fatalf("merged ranges should be completed: %+v", resumed.checkpoint.CompletedRanges)
+	}
+	if resumed.IsCompleted(1000, 1100) || resumed.IsCompleted(1050, 1150) {
+		t.Fatalf("unfinished range reported as completed")
+	}
+	if got := resumed.CompletedSlots(); got != 200 {
+		t.Fatalf("unexpected completed slots: %d", got)
+	}
+	if failed := resumed.FailedSlots(); len(failed) != 2 {
+		t.Fatalf("failed slots should be deduplicated: %v", failed)
+	}
+
+	if err := resumed.ResolveFailedSlots([]uint64{1234}); err != nil {
+		t.Fatalf("resolve failed slots: %v", err)
+	}
+	if failed := resumed.FailedSlots(); len(failed) != 1 || failed[0] != 1150 {
+		t.Fatalf("unexpected failed slots after resolve: %v", failed)
+	}
+}
+
+func TestFileCheckpointStoreRangeMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	store, err := NewFileCheckpointStore(path, 1000, 1300)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	if err := store.MarkCompleted(1000, 1100, nil); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+
+	if _, err := NewFileCheckpointStore(path, 0, 1300); err == nil {
+		t.Fatalf("expected error for mismatched range")
+	}
+}
+
+func TestLoadCheckpointStoresResolvesRetriedSlots(t *testing.T) {
+	dir := t.TempDir()
+	scan, err := NewFileCheckpointStore(filepath.Join(dir, CheckpointFileName(1000, 1300)), 1000, 1300)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	if err := scan.MarkCompleted(1000, 1100, []uint64{1010, 1020}); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+	replay, err := NewFileCheckpointStore(filepath.Join(dir, "replay_"+CheckpointFileName(2000, 2100)), 2000, 2100)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	if err := replay.MarkCompleted(2000, 2100, []uint64{2050}); err != nil {
+		t.Fatalf("mark completed: %v", err)
+	}
+
+	stores, err := LoadCheckpointStores(dir)
+	if err != nil || len(stores) != 2 {
+		t.Fatalf("LoadCheckpointStores() = %d stores, %v, want 2", len(stores), err)
+	}
+	for _, store := range stores {
+		if err := store.ResolveFailedSlots([]uint64{1010, 2050}); err != nil {
+			t.Fatalf("resolve failed slots: %v", err)
+		}
+	}
+
+	resumed, err := NewFileCheckpointStore(filepath.Join(dir, CheckpointFileName(1000, 1300)), 1000, 1300)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	if failed := resumed.FailedSlots(); len(failed) != 1 || failed[0] != 1020 {
+		t.Fatalf("failed slots after retry = %v, want [1020]", failed)
+	}
+	resumedReplay, err := NewFileCheckpointStore(filepath.Join(dir, "replay_"+CheckpointFileName(2000, 2100)), 2000, 2100)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	if failed := resumedReplay.FailedSlots(); len(failed) != 0 {
+		t.Fatalf("replay failed slots after retry = %v, want none", failed)
+	}
+}
@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/sink"
+	"github.com/go-solana-parse/src/solana"
+)
+
+// FollowChain 跟随链上最新区块，只保留通过 filter 的交易并发送到 out，直到 ctx 取消
+// filter 为空时使用配置文件 filter 段
+func FollowChain(ctx context.Context, opts solana.FollowOptions, filter solana.TransactionFilter, out sink.ParseSink) error {
+	if filter == nil {
+		filter = ConfiguredTransactionFilter()
+	}
+	return solana.Follow(ctx, opts, followBlockHandler(filter, out))
+}
+
+// followBlockHandler 过滤区块交易后发送到 out，过滤后没有交易的区块直接跳过
+func followBlockHandler(filter solana.TransactionFilter, out sink.ParseSink) solana.BlockHandler {
+	return func(slot uint64, block *model.Block) error {
+		if FilterBlockTransactions(block, filter) == 0 {
+			return nil
+		}
+		return out.Send([]model.ParseBlockDataDenoReq{{
+			BlockNum:  strconv.FormatUint(slot, 10),
+			BlockData: *block,
+		}})
+	}
+}
@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+// recordingSink 记录收到的区块
+type recordingSink struct {
+	blocks []model.ParseBlockDataDenoReq
+}
+
+func (s *recordingSink) Send(blocks []model.ParseBlockDataDenoReq) error {
+	s.blocks = append(s.blocks, blocks...)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+func TestFollowBlockHandlerSkipsFullyFilteredBlocks(t *testing.T) {
+	out := &recordingSink{}
+	rejectAll := func(tx *model.TransactionInfo) bool { return false }
+	keepAll := func(tx *model.TransactionInfo) bool { return true }
+
+	block := &model.Block{Transactions: make([]model.TransactionInfo, 2)}
+	if err := followBlockHandler(rejectAll, out)(100, block); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.blocks) != 0 {
+		t.Fatalf("sent %d blocks, want none when every transaction is filtered out", len(out.blocks))
+	}
+
+	block = &model.Block{Transactions: make([]model.TransactionInfo, 2)}
+	if err := followBlockHandler(keepAll, out)(101, block); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.blocks) != 1 || out.blocks[0].BlockNum != "101" || len(out.blocks[0].BlockData.Transactions) != 2 {
+		t.Fatalf("sent %+v, want block 101 with 2 transactions", out.blocks)
+	}
+}
@@ -0,0 +1,226 @@
+package processor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/sink"
+	"github.com/go-solana-parse/src/solana"
+	"github.com/go-solana-parse/src/util"
+)
+
+// RetryOptions 失败区块重试参数
+type RetryOptions struct {
+	APIKey         string
+	BatchSize      int                      // 单次批量 RPC 请求的区块数
+	MaxAttempts    int                      // 每个区块最多尝试次数
+	InitialBackoff time.Duration            // 首次重试前的等待时间，之后每轮翻倍
+	MaxBackoff     time.Duration            // 等待时间上限
+	Filter         solana.TransactionFilter // 交易过滤器，为空时使用配置文件 filter 段
+	Sink           sink.ParseSink           // 解析结果输出，为空时发送到 Deno 解析服务
+	Checkpoints    []*FileCheckpointStore   // 重试成功的区块从这些扫描进度的失败列表中移除
+}
+
+// RetryResult 重试结果
+type RetryResult struct {
+	Succeeded []uint64          // 获取并转发成功的区块
+	Failed    []uint64          // 重试用尽仍失败的区块（临时性错误）
+	Skipped   []uint64          // 被 leader 跳过的区块（永久性错误，无需再试）
+	Reasons   map[uint64]string // 失败与被跳过区块最近一次的失败原因
+}
+
+// DefaultRetryOptions 默认重试参数
+func DefaultRetryOptions(apiKey string) RetryOptions {
+	return RetryOptions{
+		APIKey:         apiKey,
+		BatchSize:      10,
+		MaxAttempts:    5,
+		InitialBackoff: 2 * time.Second,
+		MaxBackoff:     time.Minute,
+	}
+}
+
+// LoadFailedSlotsFiles 读取 saveFailedSlotsToFile 写出的失败区块文件，忽略 # 注释（含行尾失败原因）与空行，合并去重后升序返回
+func LoadFailedSlotsFiles(paths ...string) ([]uint64, error) {
+	seen := make(map[uint64]struct{})
+	var slots []uint64
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("打开失败区块文件失败: %v", err)
+		}
+
+		scanner := bufio.NewScanner(file)
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := scanner.Text()
+			if i := strings.Index(line, "#"); i >= 0 {
+				line = line[:i]
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			slot, err := strconv.ParseUint(line, 10, 64)
+			if err != nil {
+				file.Close()
+				return nil, fmt.Errorf("%s 第 %d 行区块号无效: %v", path, lineNum, err)
+			}
+			if _, exists := seen[slot]; !exists {
+				seen[slot] = struct{}{}
+				slots = append(slots, slot)
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取失败区块文件失败: %v", err)
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	return slots, nil
+}
+
+// RetryFailedSlotsFiles 重试失败区块文件中的全部区块，并将仍失败与被跳过的区块分别写入新文件
+func RetryFailedSlotsFiles(paths []string, opts RetryOptions) (*RetryResult, error) {
+	slots, err := LoadFailedSlotsFiles(paths...)
+	if err != nil {
+		return nil, err
+	}
+	util.Logf("🔁 从 %d 个文件加载失败区块 %d 个\n", len(paths), len(slots))
+
+	if opts.Checkpoints == nil {
+		if opts.Checkpoints, err = LoadCheckpointStores("."); err != nil {
+			return nil, err
+		}
+	}
+
+	result := RetryFailedSlots(slots, opts)
+
+	timestamp := time.Now().Format("20060102_150405")
+	if len(result.Failed) > 0 {
+		writeSlotsFile(fmt.Sprintf("failed_slots_retry_%s.txt", timestamp), []string{
+			"重试后仍失败的区块",
+			fmt.Sprintf("处理时间: %s", time.Now().Format("2006-01-02 15:04:05")),
+			fmt.Sprintf("来源文件: %s", strings.Join(paths, ", ")),
+			fmt.Sprintf("失败区块数: %d", len(result.Failed)),
+		}, result.Failed, result.Reasons)
+	}
+	if len(result.Skipped) > 0 {
+		writeSlotsFile(fmt.Sprintf("skipped_slots_%s.txt", timestamp), []string{
+			"被 leader 跳过的区块（永久性错误，无需重试）",
+			fmt.Sprintf("处理时间: %s", time.Now().Format("2006-01-02 15:04:05")),
+			fmt.Sprintf("来源文件: %s", strings.Join(paths, ", ")),
+			fmt.Sprintf("跳过区块数: %d", len(result.Skipped)),
+		}, result.Skipped, result.Reasons)
+	}
+
+	util.Logf("✅ 重试完成: 成功 %d, 仍失败 %d, 被跳过 %d\n", len(result.Succeeded), len(result.Failed), len(result.Skipped))
+	return result, nil
+}
+
+// RetryFailedSlots 按指数退避重新获取区块并转发到 sink
+// slot 被跳过属于永久性错误，立即归入 Skipped；其余错误在下一轮重试
+func RetryFailedSlots(slots []uint64, opts RetryOptions) *RetryResult {
+	result := &RetryResult{Reasons: make(map[uint64]string)}
+	pending := append([]uint64(nil), slots...)
+	backoff := opts.InitialBackoff
+	filter := opts.Filter
+	if filter == nil {
+		filter = ConfiguredTransactionFilter()
+	}
+	out := opts.Sink
+	if out == nil {
+		out = sink.NewDenoSink()
+	}
+
+	for attempt := 1; attempt <= opts.MaxAttempts && len(pending) > 0; attempt++ {
+		if attempt > 1 {
+			util.Logf("⏳ 第 %d 次重试前等待 %v，剩余 %d 个区块\n", attempt, backoff, len(pending))
+			time.Sleep(backoff)
+			backoff *= 2
+			if opts.MaxBackoff > 0 && backoff > opts.MaxBackoff {
+				backoff = opts.MaxBackoff
+			}
+		}
+
+		blocks, slotErrors := solana.GetMultipleBlocksDataWithErrors(pending, opts.APIKey, opts.BatchSize)
+
+		var fetched []uint64
+		var retry []uint64
+		for _, slot := range pending {
+			if block, ok := blocks[slot]; ok && block != nil {
+				fetched = append(fetched, slot)
+				continue
+			}
+			result.Reasons[slot] = solana.SlotErrorReason(slotErrors[slot])
+			if isPermanentSlotError(slotErrors[slot]) {
+				result.Skipped = append(result.Skipped, slot)
+				continue
+			}
+			retry = append(retry, slot)
+		}
+
+		// 每50个区块转发一次，转发失败的区块留到下一轮重新获取
+		for i := 0; i < len(fetched); i += 50 {
+			end := i + 50
+			if end > len(fetched) {
+				end = len(fetched)
+			}
+			chunk := fetched[i:end]
+
+			fullBlockData := make([]model.ParseBlockDataDenoReq, 0, len(chunk))
+			for _, slot := range chunk {
+				fullBlockData = append(fullBlockData, newParseBlockDataReq(slot, blocks[slot], filter))
+			}
+			if err := out.Send(fullBlockData); err != nil {
+				util.Logf("❌ 转发区块 %d - %d 失败: %v\n", chunk[0], chunk[len(chunk)-1], err)
+				for _, slot := range chunk {
+					result.Reasons[slot] = fmt.Sprintf("sink: %v", err)
+				}
+				retry = append(retry, chunk...)
+				continue
+			}
+			for _, slot := range chunk {
+				delete(result.Reasons, slot)
+			}
+			result.Succeeded = append(result.Succeeded, chunk...)
+			for _, checkpoint := range opts.Checkpoints {
+				if err := checkpoint.ResolveFailedSlots(chunk); err != nil {
+					util.Logf("⚠️ 更新进度文件失败: %v\n", err)
+				}
+			}
+		}
+
+		pending = retry
+	}
+
+	result.Failed = pending
+	return result
+}
+
+// isPermanentSlotError 判断区块获取错误是否为永久性（slot 被 leader 跳过），长期存储缺失（-32009）仍会重试
+func isPermanentSlotError(err error) bool {
+	var rpcErr *model.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.IsSlotSkipped()
+}
+
+// newParseBlockDataReq 构造解析请求，只保留通过 filter 的交易
+func newParseBlockDataReq(slot uint64, block *model.Block, filter solana.TransactionFilter) model.ParseBlockDataDenoReq {
+	FilterBlockTransactions(block, filter)
+
+	return model.ParseBlockDataDenoReq{
+		BlockNum:  strconv.FormatUint(slot, 10),
+		BlockData: *block,
+	}
+}
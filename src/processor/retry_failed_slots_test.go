@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+func TestLoadFailedSlotsFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "failed_slots_1.txt")
+	writeSlotsFile(first, []string{"失败区块记录", "区块范围: 100 - 200"}, []uint64{150, 120}, map[uint64]string{150: "rpc -32004: Block not available"})
+
+	second := filepath.Join(dir, "failed_slots_2.txt")
+	if err := os.WriteFile(second, []byte("# header\n\n120\n 110 \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	slots, err := LoadFailedSlotsFiles(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{110, 120, 150}; !reflect.DeepEqual(slots, want) {
+		t.Fatalf("slots = %v, want %v", slots, want)
+	}
+}
+
+func TestLoadFailedSlotsFilesInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failed_slots.txt")
+	if err := os.WriteFile(path, []byte("# header\nabc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFailedSlotsFiles(path); err == nil {
+		t.Fatal("expected error for invalid slot line")
+	}
+}
+
+func TestIsPermanentSlotError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&model.RPCError{Code: model.RPCErrorSlotSkipped, Message: "Slot 1 was skipped"}, true},
+		{&model.RPCError{Code: model.RPCErrorLongTermStorageSkipped, Message: "Slot 1 was skipped, or missing in long-term storage"}, false},
+		{&model.RPCError{Code: -32004, Message: "Block not available for slot 1"}, false},
+		{fmt.Errorf("failed to send request: timeout"), false},
+		{nil, false},
+	}
+	for _, c := range cases {
+		if got := isPermanentSlotError(c.err); got != c.want {
+			t.Errorf("isPermanentSlotError(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
@@ -1,157 +1,155 @@
 package processor
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
 	"strconv"
-	"sync"
 	"time"
 
-	"github.com/go-solana-parse/src/config"
 	"github.com/go-solana-parse/src/model"
-	rpccall "github.com/go-solana-parse/src/rpc_call"
+	"github.com/go-solana-parse/src/sink"
 	"github.com/go-solana-parse/src/solana"
+	"github.com/go-solana-parse/src/util"
 )
 
-func ScanBlockData() {
-	startTime := time.Now()
-
-	var failedSlots []uint64
-
-	// startSlot := uint64(263922023)
-	startSlot := uint64(247806009)
-
-	endSlot := uint64(347587512)
-
-	cycleSize := 100
-
-	currentBatchArr := [][]uint64{}
-
-	runtime.GOMAXPROCS(30)
-
-	for slot := endSlot; slot > startSlot; slot -= uint64(cycleSize) {
-		currentBatch := []uint64{}
-
-		for i := 0; i < cycleSize; i++ {
-			currentBatch = append(currentBatch, slot-uint64(i))
-		}
+// ScanOptions 区块扫描参数，扫描范围为 [From, To)
+type ScanOptions struct {
+	From     uint64
+	To       uint64
+	Cycle    int    // 每个 cycle 的区块数，作为进度记录的最小单位
+	Batch    int    // 单次批量 RPC 请求的区块数
+	Workers  int    // 并发处理的 cycle 数
+	Sink     string // 解析结果去向，见 sink.SinkDeno 等
+	SinkPath string // sink 为 file 时的输出路径
+	APIKey   string
+	Archive  string                   // 获取的区块同时写入该归档目录
+	Replay   string                   // 从该归档目录回放区块，不发起 RPC 请求
+	Source   solana.BlockSource       // 区块来源，为空时根据 Replay 选择本地归档或批量 JSON-RPC
+	Filter   solana.TransactionFilter // 交易过滤器，为空时使用配置文件 filter 段
+	Output   sink.ParseSink           // 解析结果输出，为空时根据 Sink 创建
+}
 
-		currentBatchArr = append(currentBatchArr, currentBatch)
+// Validate 校验扫描参数
+func (opts ScanOptions) Validate() error {
+	if opts.To <= opts.From {
+		return fmt.Errorf("区块范围无效: %d - %d", opts.From, opts.To)
 	}
-
-	batchSize := 10
-
-	// 工具函数：将二维数组切分为三维数组，每组最多30个batch
-	batchesOf30 := splitToChunks(currentBatchArr, 20)
-	for batchIndex, batchGroup := range batchesOf30 { // 外层串行
-		startTime := time.Now()
-		var wg sync.WaitGroup
-		for _, currentBatch := range batchGroup { // 内层并发
-			wg.Add(1)
-			go func(batch []uint64) {
-				defer wg.Done()
-				fmt.Println("start get block data", batch[0])
-				results := solana.GetMultipleBlocksData(batch, "3ed35a0b-35f6-4adb-8caa-5c72cd36b023", batchSize)
-
-				fullBlockData := []model.ParseBlockDataDenoReq{}
-
-				for _, slot := range batch {
-					block, exists := results[slot]
-					if !exists || block == nil {
-						failedSlots = append(failedSlots, slot)
-						continue
-					}
-					if len(block.Transactions) == 0 {
-						continue
-					}
-					transactions := []model.TransactionInfo{}
-					for _, transaction := range block.Transactions {
-						for _, account := range transaction.Transaction.Message.AccountKeys {
-							if account == "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA" {
-								transactions = append(transactions, transaction)
-								break
-							}
-						}
-					}
-					block.Transactions = transactions
-					fullBlockData = append(fullBlockData, model.ParseBlockDataDenoReq{
-						BlockNum:  strconv.Itoa(int(slot)),
-						BlockData: *block,
-					})
-				}
-
-				// wg2 := &sync.WaitGroup{}
-				// for _, block := range fullBlockData {
-				// 	wg2.Add(1)
-				// 	go func(b model.ParseBlockDataDenoReq) {
-				// 		defer wg2.Done()
-				rpccall.SendMultipleParseDataToDeno(fullBlockData)
-				// 	}(block)
-				// }
-				// wg2.Wait()
-				fmt.Println("get block data done", batch[0])
-			}(currentBatch)
+	if opts.Cycle <= 0 || opts.Batch <= 0 || opts.Workers <= 0 {
+		return fmt.Errorf("cycle、batch、workers 必须大于 0")
+	}
+	if opts.Output == nil {
+		if err := sink.Validate(opts.Sink, opts.SinkPath); err != nil {
+			return err
 		}
-		wg.Wait() // 等待这一组30个全部完成
-		elapsed := time.Since(startTime)
-		fmt.Printf("🚀 多核处理完成 开始slot: %d 结束slot: %d 总耗时: %.1fs\n", batchGroup[batchIndex][0], batchGroup[batchIndex][len(batchGroup[batchIndex])-1], elapsed.Seconds())
 	}
-
-	fmt.Println("failedSlots ", failedSlots)
-
-	elapsed := time.Since(startTime)
-	fmt.Printf("🚀 多核处理完成: 总耗时: %.1fs\n", elapsed.Seconds())
+	if opts.Source == nil && opts.Replay == "" {
+		return solana.RequireRPCEndpoint(opts.APIKey)
+	}
+	return nil
 }
 
-func getData() {
-	config.LoadSvcConfig()
-
-	// 🚀 启用多核CPU支持
-	numCPU := runtime.NumCPU()
-	runtime.GOMAXPROCS(numCPU)
-	fmt.Printf("🔥 多核CPU模式启用: 使用 %d 个CPU核心\n", numCPU)
-
-	// 支持命令行参数：go run main.go [processId] [startSlot] [endSlot] [cycleSize] [batchSize] [portStart] [processCount]
-	// var processId, startSlot, endSlot, cycleSize, batchSize, portStart, processCount int
-
-	timeStart := time.Now()
-
-	startSlot := 337199528
-	endSlot := startSlot + 500
-	cycleSize := 100
-	batchSize := 10
+// blockSource 创建扫描使用的区块来源：Replay 从本地归档回放，Archive 将获取的区块同时写入归档
+// 返回的 close 函数在扫描结束后释放归档文件
+func (opts ScanOptions) blockSource() (solana.BlockSource, func(), error) {
+	source := opts.Source
+	closers := []func(){}
+	if source == nil && opts.Replay != "" {
+		fileSource, err := solana.NewFileBlockSource(opts.Replay)
+		if err != nil {
+			return nil, nil, err
+		}
+		util.Logf("📂 从本地归档回放区块: %s\n", opts.Replay)
+		source = fileSource
+		closers = append(closers, fileSource.Close)
+	}
+	if source == nil {
+		// 归档需要保留完整区块，不归档时解码阶段就丢弃无关交易
+		var filter solana.TransactionFilter
+		if opts.Archive == "" {
+			filter = opts.Filter
+		}
+		source = solana.NewBlockSource(opts.APIKey, opts.Batch, filter)
+	}
 
-	processContinuousBlocksMultiCore(startSlot, endSlot, cycleSize, batchSize, numCPU)
+	if opts.Archive != "" {
+		archive, err := solana.OpenBlockArchive(opts.Archive, solana.DefaultArchiveShardSize)
+		if err != nil {
+			for _, closeFn := range closers {
+				closeFn()
+			}
+			return nil, nil, fmt.Errorf("打开区块归档失败: %v", err)
+		}
+		util.Logf("🗄️ 获取的区块将归档到: %s\n", opts.Archive)
+		source = solana.NewArchivingBlockSource(source, archive)
+		closers = append(closers, func() {
+			if err := archive.Close(); err != nil {
+				util.Logf("❌ 关闭区块归档失败: %v\n", err)
+			}
+		})
+	}
 
-	timeEnd := time.Now()
-	fmt.Printf("🎉 多核处理完成: %d 区块, 总耗时: %.1fs\n",
-		endSlot-startSlot, timeEnd.Sub(timeStart).Seconds())
+	return source, func() {
+		for _, closeFn := range closers {
+			closeFn()
+		}
+	}, nil
 }
 
-// 多核CPU版本：持续循环处理区块
-func processContinuousBlocksMultiCore(startSlot, endSlot, cycleSize, batchSize, numCPU int) {
+// ScanRange 多核倒序扫描区块范围：按 cycle 拆分任务并发处理，重启后跳过进度文件中已完成的 cycle
+func ScanRange(opts ScanOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
 
 	// 计算此进程的实际slot范围
-	myStartSlot := startSlot
-	myEndSlot := endSlot
+	myStartSlot := int(opts.From)
+	myEndSlot := int(opts.To)
 	myTotalBlocks := myEndSlot - myStartSlot
+	cycleSize := opts.Cycle
 
 	totalCycles := (myTotalBlocks + cycleSize - 1) / cycleSize
 
-	fmt.Printf("🎬 负责区块范围: %d - %d (%d 个区块) [多核并行]\n",
+	util.Logf("🎬 负责区块范围: %d - %d (%d 个区块) [多核并行]\n",
 		myStartSlot, myEndSlot-1, myTotalBlocks)
 
-	overallStartTime := time.Now()
+	// 📌 加载扫描进度，重启后跳过已完成的cycle
+	// 回放归档使用独立的进度文件，不受之前在线扫描进度的影响
+	checkpointPath := CheckpointFileName(opts.From, opts.To)
+	if opts.Replay != "" {
+		checkpointPath = "replay_" + checkpointPath
+	}
+	checkpoint, err := NewFileCheckpointStore(checkpointPath, opts.From, opts.To)
+	if err != nil {
+		return fmt.Errorf("加载扫描进度失败: %v", err)
+	}
 
-	// 🚀 多核并行处理：创建多个goroutine并行处理不同的cycle
-	// 根据CPU核心数和cycle数量决定并发goroutine数量
-	maxConcurrentCycles := numCPU
-	if maxConcurrentCycles > totalCycles {
-		maxConcurrentCycles = totalCycles
+	if opts.Filter == nil {
+		opts.Filter = ConfiguredTransactionFilter()
+	}
+
+	// 所有worker共享同一个区块来源，限流与节点健康状态全局生效
+	source, closeSource, err := opts.blockSource()
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+
+	// 所有worker共享同一个输出
+	out := opts.Output
+	if out == nil {
+		if out, err = sink.NewParseSink(opts.Sink, opts.SinkPath); err != nil {
+			return fmt.Errorf("创建 sink 失败: %v", err)
+		}
+		defer func() {
+			if err := out.Close(); err != nil {
+				util.Logf("❌ 关闭 sink 失败: %v\n", err)
+			}
+		}()
 	}
 
-	fmt.Printf("🔥 启动 %d 个并发goroutine处理 %d 个cycle\n", maxConcurrentCycles, totalCycles)
+	overallStartTime := time.Now()
 
 	// 创建用于传递cycle任务的channel
 	type CycleTask struct {
@@ -166,68 +164,99 @@ func processContinuousBlocksMultiCore(startSlot, endSlot, cycleSize, batchSize,
 		processedCount int
 		elapsed        time.Duration
 		failedSlots    []uint64
+		failedReasons  map[uint64]string
 		err            error
 	}
 
+	// 🔄 生成倒序cycle任务
+	var pendingTasks []CycleTask
+	for cycle := 0; cycle < totalCycles; cycle++ {
+		// 倒序计算：从最后的区块开始
+		cycleEndSlot := myEndSlot - cycle*cycleSize
+		cycleStartSlot := cycleEndSlot - cycleSize
+
+		// 确保不超过此进程的范围
+		if cycleStartSlot < myStartSlot {
+			cycleStartSlot = myStartSlot
+		}
+
+		actualBlocks := cycleEndSlot - cycleStartSlot
+
+		// 如果没有区块需要处理或之前已完成，跳过
+		if actualBlocks <= 0 || checkpoint.IsCompleted(uint64(cycleStartSlot), uint64(cycleEndSlot)) {
+			continue
+		}
+
+		pendingTasks = append(pendingTasks, CycleTask{
+			cycleIndex:     cycle,
+			cycleStartSlot: cycleStartSlot,
+			cycleEndSlot:   cycleEndSlot,
+			actualBlocks:   actualBlocks,
+		})
+	}
+
+	if skipped := totalCycles - len(pendingTasks); skipped > 0 {
+		util.Logf("📌 跳过已完成的 %d 个cycle，剩余 %d 个cycle\n", skipped, len(pendingTasks))
+	}
+	totalCycles = len(pendingTasks)
+	if totalCycles == 0 {
+		util.Logf("🎉 区块范围已全部完成\n")
+		return nil
+	}
+
+	// 🚀 多核并行处理：创建多个goroutine并行处理不同的cycle
+	// 根据CPU核心数和cycle数量决定并发goroutine数量
+	maxConcurrentCycles := opts.Workers
+	if maxConcurrentCycles > totalCycles {
+		maxConcurrentCycles = totalCycles
+	}
+
+	util.Logf("🔥 启动 %d 个并发goroutine处理 %d 个cycle\n", maxConcurrentCycles, totalCycles)
+
 	cycleTasks := make(chan CycleTask, totalCycles)
 	cycleResults := make(chan CycleResult, totalCycles)
 
 	// 启动worker goroutines
 	for i := 0; i < maxConcurrentCycles; i++ {
 		go func(workerID int) {
-			fmt.Printf("🔧 Worker %d 启动\n", workerID)
+			util.Logf("🔧 Worker %d 启动\n", workerID)
 			for task := range cycleTasks {
 				cycleStartTime := time.Now()
 
 				// 使用新的分批处理逻辑，包含失败记录
-				processedCount, failedSlots := processSingleRangeHighSpeedMultiCoreWithFailureTracking(
+				processedCount, failedSlots, failedReasons := processSingleRangeHighSpeedMultiCoreWithFailureTracking(
 					uint64(task.cycleStartSlot),
 					uint64(task.cycleEndSlot),
-					batchSize,
+					source,
+					opts.Filter,
+					out,
 				)
 
 				cycleElapsed := time.Since(cycleStartTime)
 
+				if err := checkpoint.MarkCompleted(uint64(task.cycleStartSlot), uint64(task.cycleEndSlot), failedSlots); err != nil {
+					util.Logf("❌ 保存扫描进度失败: %v\n", err)
+				}
+
 				cycleResults <- CycleResult{
 					cycleIndex:     task.cycleIndex,
 					processedCount: processedCount,
 					elapsed:        cycleElapsed,
 					failedSlots:    failedSlots,
+					failedReasons:  failedReasons,
 					err:            nil,
 				}
 
-				fmt.Printf("✅ Worker %d 完成 cycle %d: %d 区块, %.1fs\n",
+				util.Logf("✅ Worker %d 完成 cycle %d: %d 区块, %.1fs\n",
 					workerID, task.cycleIndex+1, task.actualBlocks, cycleElapsed.Seconds())
 			}
 		}(i)
 	}
 
-	// 🔄 生成倒序cycle任务
 	go func() {
 		defer close(cycleTasks)
-		for cycle := 0; cycle < totalCycles; cycle++ {
-			// 倒序计算：从最后的区块开始
-			cycleEndSlot := myEndSlot - cycle*cycleSize
-			cycleStartSlot := cycleEndSlot - cycleSize
-
-			// 确保不超过此进程的范围
-			if cycleStartSlot < myStartSlot {
-				cycleStartSlot = myStartSlot
-			}
-
-			actualBlocks := cycleEndSlot - cycleStartSlot
-
-			// 如果没有区块需要处理，跳过
-			if actualBlocks <= 0 {
-				continue
-			}
-
-			cycleTasks <- CycleTask{
-				cycleIndex:     cycle,
-				cycleStartSlot: cycleStartSlot,
-				cycleEndSlot:   cycleEndSlot,
-				actualBlocks:   actualBlocks,
-			}
+		for _, task := range pendingTasks {
+			cycleTasks <- task
 		}
 	}()
 
@@ -235,24 +264,28 @@ func processContinuousBlocksMultiCore(startSlot, endSlot, cycleSize, batchSize,
 	totalProcessedBlocks := 0
 	completedCycles := 0
 	var allFailedSlots []uint64
+	failureReasons := make(map[uint64]string)
 
 	for result := range cycleResults {
 		totalProcessedBlocks += result.processedCount
 		allFailedSlots = append(allFailedSlots, result.failedSlots...)
+		for slot, reason := range result.failedReasons {
+			failureReasons[slot] = reason
+		}
 		completedCycles++
 
 		// 🧠 内存监控（每5个cycle）
 		if completedCycles%5 == 0 {
 			var memStats runtime.MemStats
 			runtime.ReadMemStats(&memStats)
-			fmt.Printf("💾 内存状态: %.1f MB (Heap: %.1f MB)\n",
+			util.Logf("💾 内存状态: %.1f MB (Heap: %.1f MB)\n",
 				float64(memStats.Sys)/1024/1024, float64(memStats.HeapAlloc)/1024/1024)
 		}
 
 		// 🧹 垃圾回收（每10个cycle）
 		if completedCycles%10 == 0 {
 			runtime.GC()
-			fmt.Printf("🧹 内存清理完成\n")
+			util.Logf("🧹 内存清理完成\n")
 		}
 
 		// 计算进度
@@ -263,24 +296,27 @@ func processContinuousBlocksMultiCore(startSlot, endSlot, cycleSize, batchSize,
 			break
 		}
 
-		fmt.Printf("📈 多核进度: %.1f%% (%d/%d), 已用时: %.1fm\n",
+		util.Logf("📈 多核进度: %.1f%% (%d/%d), 已用时: %.1fm\n",
 			progress, completedCycles, totalCycles, overallElapsed.Minutes())
 	}
 
-	// 保存失败的区块到文件
-	if len(allFailedSlots) > 0 {
-		saveFailedSlotsToFile(allFailedSlots, uint64(myStartSlot), uint64(myEndSlot))
+	// 保存失败的区块到文件（包含之前运行中记录的失败区块）
+	if failed := checkpoint.FailedSlots(); len(failed) > 0 {
+		saveFailedSlotsToFile(failed, failureReasons, uint64(myStartSlot), uint64(myEndSlot))
 	}
 
 	overallElapsed := time.Since(overallStartTime)
-	fmt.Printf("🎉 多核处理完成: %d 区块, 失败: %d 区块, 总耗时: %.1fm\n",
+	util.Logf("🎉 多核处理完成: %d 区块, 失败: %d 区块, 总耗时: %.1fm\n",
 		totalProcessedBlocks, len(allFailedSlots), overallElapsed.Minutes())
+	return nil
 }
 
 // 多核优化版本的处理函数（带失败跟踪）
-func processSingleRangeHighSpeedMultiCoreWithFailureTracking(startSlot, endSlot uint64, batchSize int) (int, []uint64) {
+// 返回处理的区块数、失败的区块及失败原因
+func processSingleRangeHighSpeedMultiCoreWithFailureTracking(startSlot, endSlot uint64, source solana.BlockSource, filter solana.TransactionFilter, out sink.ParseSink) (int, []uint64, map[uint64]string) {
 	// 创建失败记录
 	var failedSlots []uint64
+	failedReasons := make(map[uint64]string)
 	totalProcessedBlocks := 0
 	totalFilteredTxs := 0
 
@@ -304,10 +340,10 @@ func processSingleRangeHighSpeedMultiCoreWithFailureTracking(startSlot, endSlot
 
 		currentBatch := reversedSlots[i:batchEnd]
 
-		fmt.Printf("🚀 多核处理: %d - %d, batchSize: %d\n", currentBatch[0], currentBatch[len(currentBatch)-1], batchSize)
+		util.Logf("🚀 多核处理: %d - %d\n", currentBatch[0], currentBatch[len(currentBatch)-1])
 
 		// 获取这一小批的区块数据
-		results := solana.GetMultipleBlocksData(currentBatch, "3ed35a0b-35f6-4adb-8caa-5c72cd36b023", batchSize)
+		results, slotErrors := solana.CollectBlocks(context.Background(), source, currentBatch)
 
 		// 处理结果
 		var fullBlockData []model.ParseBlockDataDenoReq
@@ -317,8 +353,9 @@ func processSingleRangeHighSpeedMultiCoreWithFailureTracking(startSlot, endSlot
 		for _, slot := range currentBatch {
 			block, exists := results[slot]
 			if !exists || block == nil {
-				// 记录获取失败的区块
+				// 记录获取失败的区块及原因
 				failedSlots = append(failedSlots, slot)
+				failedReasons[slot] = solana.SlotErrorReason(slotErrors[slot])
 				continue
 			}
 
@@ -328,22 +365,8 @@ func processSingleRangeHighSpeedMultiCoreWithFailureTracking(startSlot, endSlot
 				continue
 			}
 
-			// 过滤包含特定token的交易
-			transactions := []model.TransactionInfo{}
-			for _, transaction := range block.Transactions {
-				for _, account := range transaction.Transaction.Message.AccountKeys {
-					if account == "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA" {
-						transactions = append(transactions, transaction)
-						break
-					}
-				}
-			}
-
-			if len(transactions) > 0 {
-				batchFilteredTxs += len(transactions)
-			}
-
-			block.Transactions = transactions
+			// 过滤交易
+			batchFilteredTxs += FilterBlockTransactions(block, filter)
 
 			fullBlockData = append(fullBlockData, model.ParseBlockDataDenoReq{
 				BlockNum:  strconv.Itoa(int(slot)),
@@ -353,14 +376,15 @@ func processSingleRangeHighSpeedMultiCoreWithFailureTracking(startSlot, endSlot
 			batchProcessedBlocks++
 		}
 
-		// 发送这一小批数据到RPC并等待返回
+		// 发送这一小批数据到 sink 并等待返回
 		if len(fullBlockData) > 0 {
-			err := rpccall.SendMultipleParseDataToDeno(fullBlockData)
+			err := out.Send(fullBlockData)
 			if err != nil {
 				// 将这一小批的所有区块都标记为失败
 				for _, data := range fullBlockData {
 					if slotInt, parseErr := strconv.ParseUint(data.BlockNum, 10, 64); parseErr == nil {
 						failedSlots = append(failedSlots, slotInt)
+						failedReasons[slotInt] = fmt.Sprintf("sink: %v", err)
 					}
 				}
 			}
@@ -374,11 +398,11 @@ func processSingleRangeHighSpeedMultiCoreWithFailureTracking(startSlot, endSlot
 		fullBlockData = nil
 	}
 
-	return totalProcessedBlocks, failedSlots
+	return totalProcessedBlocks, failedSlots, failedReasons
 }
 
-// saveFailedSlotsToFile 保存失败的区块到文件
-func saveFailedSlotsToFile(failedSlots []uint64, startSlot, endSlot uint64) {
+// saveFailedSlotsToFile 保存失败的区块到文件，reasons 中有记录的区块附带失败原因
+func saveFailedSlotsToFile(failedSlots []uint64, reasons map[uint64]string, startSlot, endSlot uint64) {
 	if len(failedSlots) == 0 {
 		return
 	}
@@ -387,38 +411,38 @@ func saveFailedSlotsToFile(failedSlots []uint64, startSlot, endSlot uint64) {
 	timestamp := time.Now().Format("20060102_150405")
 	filename := fmt.Sprintf("failed_slots_%d_%d_%s.txt", startSlot, endSlot, timestamp)
 
+	writeSlotsFile(filename, []string{
+		"失败区块记录",
+		fmt.Sprintf("处理时间: %s", time.Now().Format("2006-01-02 15:04:05")),
+		fmt.Sprintf("区块范围: %d - %d", startSlot, endSlot),
+		fmt.Sprintf("失败区块数: %d", len(failedSlots)),
+	}, failedSlots, reasons)
+}
+
+// writeSlotsFile 写入区块列表文件：# 开头的注释头信息，之后每行一个区块号，有失败原因时以 # 注释附在行尾
+func writeSlotsFile(filename string, headers []string, slots []uint64, reasons map[uint64]string) {
 	// 创建文件
 	file, err := os.Create(filename)
 	if err != nil {
-		fmt.Printf("❌ 创建失败记录文件失败: %v\n", err)
+		util.Logf("❌ 创建失败记录文件失败: %v\n", err)
 		return
 	}
 	defer file.Close()
 
 	// 写入文件头信息
-	file.WriteString(fmt.Sprintf("# 失败区块记录\n"))
-	file.WriteString(fmt.Sprintf("# 处理时间: %s\n", time.Now().Format("2006-01-02 15:04:05")))
-	file.WriteString(fmt.Sprintf("# 区块范围: %d - %d\n", startSlot, endSlot))
-	file.WriteString(fmt.Sprintf("# 失败区块数: %d\n", len(failedSlots)))
+	for _, header := range headers {
+		file.WriteString(fmt.Sprintf("# %s\n", header))
+	}
 	file.WriteString("# ==========================================\n")
 
-	// 写入失败的区块号
-	for _, slot := range failedSlots {
+	// 写入区块号
+	for _, slot := range slots {
+		if reason := reasons[slot]; reason != "" {
+			file.WriteString(fmt.Sprintf("%d # %s\n", slot, reason))
+			continue
+		}
 		file.WriteString(fmt.Sprintf("%d\n", slot))
 	}
 
-	fmt.Printf("📝 失败区块已保存到文件: %s (%d 个区块)\n", filename, len(failedSlots))
-}
-
-// 工具函数：将二维数组切分为三维数组，每组最多30个batch
-func splitToChunks(arr [][]uint64, chunkSize int) [][][]uint64 {
-	var chunks [][][]uint64
-	for i := 0; i < len(arr); i += chunkSize {
-		end := i + chunkSize
-		if end > len(arr) {
-			end = len(arr)
-		}
-		chunks = append(chunks, arr[i:end])
-	}
-	return chunks
+	util.Logf("📝 区块列表已保存到文件: %s (%d 个区块)\n", filename, len(slots))
 }
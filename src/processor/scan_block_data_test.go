@@ -0,0 +1,151 @@
+package processor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/sink"
+	"github.com/go-solana-parse/src/util"
+)
+
+const (
+	testSigner = "5TLRz619uQoDEPtyUK2z4NLVMQF6xrV9hYPRFMXqbNRV"
+	testMint   = "F3QEA7LhaUmVVcPTdRCi62hAYd8bLwPbNgwbwPh6SE4A"
+)
+
+// staticBlockSource 每个 slot 都返回同一个区块
+type staticBlockSource struct {
+	block model.Block
+}
+
+func (s staticBlockSource) FetchBlocks(ctx context.Context, slots []uint64) <-chan model.BlockResult {
+	out := make(chan model.BlockResult, len(slots))
+	for _, slot := range slots {
+		block := s.block
+		out <- model.BlockResult{Slot: slot, Block: &block}
+	}
+	close(out)
+	return out
+}
+
+// newRaydiumSwapTransaction 构造 1 SOL 买入 1000 个代币的 Raydium V4 兑换交易
+func newRaydiumSwapTransaction() model.TransactionInfo {
+	const authority = "raydiumAuthority111111111111111111111111111"
+
+	tx := model.TransactionInfo{}
+	tx.Transaction.Signatures = []string{"scanSignature"}
+	tx.Transaction.Message.AccountKeys = []string{
+		testSigner,
+		"userTokenAccount1111111111111111111111111111",
+		config.DEX_PROGRAMS["RAYDIUM_V4"].ID,
+		"poolTokenAccount1111111111111111111111111111",
+		"poolSolVault1111111111111111111111111111111",
+		config.SYSTEM_PROGRAM_ID,
+		config.TOKEN_PROGRAM_ID,
+		authority,
+		"testPool11111111111111111111111111111111111",
+	}
+
+	swap := make([]byte, 17)
+	swap[0] = 9 // swapBaseIn
+	binary.LittleEndian.PutUint64(swap[1:], 1_000_000_000)
+	solTransfer := make([]byte, 12)
+	binary.LittleEndian.PutUint32(solTransfer, 2)
+	binary.LittleEndian.PutUint64(solTransfer[4:], 1_000_000_000)
+	tokenTransfer := make([]byte, 9)
+	tokenTransfer[0] = 3
+	binary.LittleEndian.PutUint64(tokenTransfer[1:], 1_000_000_000)
+
+	tx.Transaction.Message.Instructions = []model.TransactionInstruction{
+		{ProgramIdIndex: 2, Accounts: []int{6, 8, 7, 4, 3, 1, 0}, Data: util.Base58Encode(swap)},
+	}
+	tx.Meta = &model.TransactionMeta{
+		Fee:          5000,
+		PreBalances:  []uint64{2_000_005_000, 2039280, 1, 2039280, 5_000_000_000, 1, 1, 0, 6124800},
+		PostBalances: []uint64{1_000_000_000, 2039280, 1, 2039280, 6_000_000_000, 1, 1, 0, 6124800},
+		InnerInstructions: []model.InnerInstruction{
+			{Index: 0, Instructions: []model.TransactionInstruction{
+				{ProgramIdIndex: 5, Accounts: []int{0, 4}, Data: util.Base58Encode(solTransfer)},
+				{ProgramIdIndex: 6, Accounts: []int{3, 1, 7}, Data: util.Base58Encode(tokenTransfer)},
+			}},
+		},
+		PreTokenBalances: []model.TokenBalance{
+			{AccountIndex: 1, Mint: testMint, Owner: testSigner, UiTokenAmount: model.UiTokenAmount{Amount: "0", Decimals: 6}},
+			{AccountIndex: 3, Mint: testMint, Owner: authority, UiTokenAmount: model.UiTokenAmount{Amount: "5000000000", Decimals: 6}},
+		},
+		PostTokenBalances: []model.TokenBalance{
+			{AccountIndex: 1, Mint: testMint, Owner: testSigner, UiTokenAmount: model.UiTokenAmount{Amount: "1000000000", Decimals: 6}},
+			{AccountIndex: 3, Mint: testMint, Owner: authority, UiTokenAmount: model.UiTokenAmount{Amount: "4000000000", Decimals: 6}},
+		},
+	}
+	return tx
+}
+
+func TestScanRangeStdoutSinkWritesOnlyNDJSON(t *testing.T) {
+	// 扫描进度与失败记录写入当前目录
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	captured := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		captured <- data
+	}()
+
+	blockTime := int64(1700000000)
+	source := staticBlockSource{block: model.Block{
+		BlockTime:    &blockTime,
+		Transactions: []model.TransactionInfo{newRaydiumSwapTransaction()},
+	}}
+	scanErr := ScanRange(ScanOptions{
+		From:    100,
+		To:      104,
+		Cycle:   2,
+		Batch:   2,
+		Workers: 2,
+		Sink:    sink.SinkStdout,
+		Source:  source,
+		Filter:  func(tx *model.TransactionInfo) bool { return true },
+	})
+	os.Stdout = stdout
+	w.Close()
+	output := <-captured
+	if scanErr != nil {
+		t.Fatalf("ScanRange() error = %v", scanErr)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		var trade map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &trade); err != nil {
+			t.Fatalf("stdout line %q is not JSON: %v", scanner.Text(), err)
+		}
+		lines++
+	}
+	if lines != 4 {
+		t.Fatalf("stdout has %d trades, want one per block:\n%s", lines, output)
+	}
+}
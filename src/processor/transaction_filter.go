@@ -0,0 +1,30 @@
+package processor
+
+import (
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/solana"
+	"github.com/go-solana-parse/src/util"
+)
+
+// ConfiguredTransactionFilter 根据配置文件 filter 段构建交易过滤器，配置无效时回退到默认过滤器
+func ConfiguredTransactionFilter() solana.TransactionFilter {
+	filter, err := solana.NewTransactionFilter(config.SvcConfig.Filter)
+	if err != nil {
+		util.Logf("⚠️ 交易过滤配置无效，使用默认过滤器: %v\n", err)
+		return solana.DefaultTransactionFilter()
+	}
+	return filter
+}
+
+// FilterBlockTransactions 只保留区块中通过 filter 的交易，返回保留的交易数
+func FilterBlockTransactions(block *model.Block, filter solana.TransactionFilter) int {
+	transactions := []model.TransactionInfo{}
+	for i := range block.Transactions {
+		if filter(&block.Transactions[i]) {
+			transactions = append(transactions, block.Transactions[i])
+		}
+	}
+	block.Transactions = transactions
+	return len(transactions)
+}
@@ -0,0 +1,331 @@
+package rpccall
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/util"
+)
+
+// 解析服务 worker 负载均衡参数
+const (
+	parseBlockDataPath         = "/api/parse-blockdata"
+	defaultPortStart           = 8000
+	defaultPortCount           = 30
+	workerEjectConsecutive     = 3                // 连续失败次数达到该值时剔除
+	workerEjectDuration        = 30 * time.Second // 剔除时长，期间健康检查通过会提前恢复
+	workerMaxAttempts          = 2                // 单次请求最多尝试的 worker 数
+	defaultHealthCheckInterval = 10 * time.Second
+	healthCheckTimeout         = 2 * time.Second
+)
+
+// Worker 解析服务 worker 及其状态，字段由 LoadBalancer 加锁访问
+type Worker struct {
+	URL string // 基础地址，如 http://localhost:8000
+
+	outstanding         int
+	requests            uint64
+	failures            uint64
+	consecutiveFailures int
+	ejectedUntil        time.Time
+}
+
+// WorkerStats worker 统计
+type WorkerStats struct {
+	URL          string
+	Outstanding  int
+	Requests     uint64
+	Failures     uint64
+	Healthy      bool
+	EjectedUntil time.Time
+}
+
+// LoadBalancer 在解析服务 worker 间分配请求：选择进行中请求最少的健康 worker，
+// 连续失败的 worker 暂时剔除，主动健康检查通过或剔除到期后恢复
+type LoadBalancer struct {
+	mu      sync.Mutex
+	workers []*Worker
+	next    int // 进行中请求数相同时轮询的起点
+
+	healthClient *http.Client
+	stop         chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewLoadBalancer 创建负载均衡器，urls 为 worker 基础地址，未带协议时默认 http://
+func NewLoadBalancer(urls []string) (*LoadBalancer, error) {
+	lb := &LoadBalancer{
+		healthClient: &http.Client{Timeout: healthCheckTimeout},
+		stop:         make(chan struct{}),
+	}
+	if err := lb.SetWorkers(urls); err != nil {
+		return nil, err
+	}
+	return lb, nil
+}
+
+var (
+	defaultLoadBalancerOnce sync.Once
+	defaultLoadBalancer     *LoadBalancer
+)
+
+// DefaultLoadBalancer 共享负载均衡器：按配置文件 rpc_call 段创建并启动健康检查
+// 未配置 workers 时使用 localhost 上从 port_start 开始的 port_count 个端口
+func DefaultLoadBalancer() *LoadBalancer {
+	defaultLoadBalancerOnce.Do(func() {
+		cfg := config.SvcConfig.RpcCall
+		urls := cfg.Workers
+		if len(urls) == 0 {
+			urls = localWorkerURLs(cfg.PortStart, cfg.PortCount)
+		}
+		lb, err := NewLoadBalancer(urls)
+		if err != nil {
+			util.Logf("⚠️ 解析服务 worker 配置无效: %v，使用 localhost 端口\n", err)
+			lb, _ = NewLoadBalancer(localWorkerURLs(0, 0))
+		}
+
+		interval := defaultHealthCheckInterval
+		if cfg.HealthCheckInterval != "" {
+			if d, err := time.ParseDuration(cfg.HealthCheckInterval); err == nil && d > 0 {
+				interval = d
+			} else {
+				util.Logf("⚠️ health_check_interval 配置无效: %s，使用 %v\n", cfg.HealthCheckInterval, interval)
+			}
+		}
+		lb.StartHealthCheck(interval)
+		defaultLoadBalancer = lb
+	})
+	return defaultLoadBalancer
+}
+
+// localWorkerURLs localhost 上从 startPort 开始的 count 个端口，参数为 0 时使用默认值
+func localWorkerURLs(startPort, count int) []string {
+	if startPort <= 0 {
+		startPort = defaultPortStart
+	}
+	if count <= 0 {
+		count = defaultPortCount
+	}
+	urls := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		urls = append(urls, fmt.Sprintf("http://localhost:%d", startPort+i))
+	}
+	return urls
+}
+
+// SetWorkers 替换 worker 列表，统计与剔除状态重新开始
+func (lb *LoadBalancer) SetWorkers(urls []string) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("load balancer requires at least one worker")
+	}
+
+	workers := make([]*Worker, 0, len(urls))
+	for _, url := range urls {
+		url = strings.TrimRight(strings.TrimSpace(url), "/")
+		if url == "" {
+			return fmt.Errorf("worker url is empty")
+		}
+		if !strings.Contains(url, "://") {
+			url = "http://" + url
+		}
+		workers = append(workers, &Worker{URL: url})
+	}
+
+	lb.mu.Lock()
+	lb.workers = workers
+	lb.next = 0
+	lb.mu.Unlock()
+	return nil
+}
+
+// Post 将 data 发送到一个 worker 的解析接口，失败时换一个 worker 重试，都失败才返回错误
+// 4xx 说明请求本身有问题，直接返回且不计入 worker 失败
+func (lb *LoadBalancer) Post(data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	lb.mu.Lock()
+	attempts := len(lb.workers)
+	lb.mu.Unlock()
+	if attempts > workerMaxAttempts {
+		attempts = workerMaxAttempts
+	}
+
+	tried := make(map[*Worker]bool)
+	for attempt := 1; ; attempt++ {
+		worker := lb.acquire(tried)
+		tried[worker] = true
+		_, err = util.PostJSON(worker.URL+parseBlockDataPath, body)
+		if isClientError(err) {
+			lb.release(worker, nil)
+			return fmt.Errorf("worker %s: %v", worker.URL, err)
+		}
+		lb.release(worker, err)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("worker %s: %v", worker.URL, err)
+		}
+		util.Logf("⚠️ 解析服务 worker %s 请求失败: %v，换一个 worker 重试\n", worker.URL, err)
+	}
+}
+
+// isClientError 判断是否为 4xx 响应：传输错误与 5xx 才计入 worker 失败
+func isClientError(err error) bool {
+	var statusErr *util.HTTPStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500
+}
+
+// acquire 选择进行中请求最少的健康 worker 并计入进行中请求，exclude 中的 worker 仅在没有其他可用 worker 时使用
+// 所有 worker 都被剔除时选择最早恢复的 worker，保证请求不会因没有可用 worker 而中断
+func (lb *LoadBalancer) acquire(exclude map[*Worker]bool) *Worker {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	now := time.Now()
+	var best *Worker
+	for _, onlyUnexcluded := range []bool{true, false} {
+		for i := range lb.workers {
+			worker := lb.workers[(lb.next+i)%len(lb.workers)]
+			if (onlyUnexcluded && exclude[worker]) || now.Before(worker.ejectedUntil) {
+				continue
+			}
+			if best == nil || worker.outstanding < best.outstanding {
+				best = worker
+			}
+		}
+		if best != nil {
+			break
+		}
+	}
+
+	if best == nil {
+		for _, worker := range lb.workers {
+			if best == nil || worker.ejectedUntil.Before(best.ejectedUntil) {
+				best = worker
+			}
+		}
+	}
+
+	lb.next = (lb.next + 1) % len(lb.workers)
+	best.outstanding++
+	return best
+}
+
+// release 请求结束，记录结果，连续失败达到阈值时剔除 worker
+func (lb *LoadBalancer) release(worker *Worker, err error) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	worker.outstanding--
+	worker.requests++
+	if err == nil {
+		worker.consecutiveFailures = 0
+		return
+	}
+
+	worker.failures++
+	worker.consecutiveFailures++
+	if worker.consecutiveFailures >= workerEjectConsecutive && !time.Now().Before(worker.ejectedUntil) {
+		lb.eject(worker)
+	}
+}
+
+// eject 剔除 worker，调用方需持有锁
+func (lb *LoadBalancer) eject(worker *Worker) {
+	worker.ejectedUntil = time.Now().Add(workerEjectDuration)
+	worker.consecutiveFailures = 0
+	util.Logf("🚫 解析服务 worker %s 暂时剔除 %v (失败 %d/%d)\n", worker.URL, workerEjectDuration, worker.failures, worker.requests)
+}
+
+// StartHealthCheck 每隔 interval 探测一次全部 worker，直到 Close
+func (lb *LoadBalancer) StartHealthCheck(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lb.stop:
+				return
+			case <-ticker.C:
+				lb.CheckHealth()
+			}
+		}
+	}()
+}
+
+// CheckHealth 探测全部 worker：有 HTTP 响应且状态码小于 500 视为存活
+// 无法连接的 worker 立即剔除，被剔除但已恢复响应的 worker 重新启用
+func (lb *LoadBalancer) CheckHealth() {
+	lb.mu.Lock()
+	workers := append([]*Worker(nil), lb.workers...)
+	lb.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, worker := range workers {
+		wg.Add(1)
+		go func(worker *Worker) {
+			defer wg.Done()
+			alive := lb.probe(worker)
+
+			lb.mu.Lock()
+			defer lb.mu.Unlock()
+			ejected := time.Now().Before(worker.ejectedUntil)
+			switch {
+			case alive && ejected:
+				worker.ejectedUntil = time.Time{}
+				worker.consecutiveFailures = 0
+				util.Logf("✅ 解析服务 worker %s 已恢复\n", worker.URL)
+			case !alive && !ejected:
+				lb.eject(worker)
+			}
+		}(worker)
+	}
+	wg.Wait()
+}
+
+// probe 请求 worker 根路径
+func (lb *LoadBalancer) probe(worker *Worker) bool {
+	resp, err := lb.healthClient.Get(worker.URL + "/")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < http.StatusInternalServerError
+}
+
+// Stats 返回各 worker 统计
+func (lb *LoadBalancer) Stats() []WorkerStats {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]WorkerStats, 0, len(lb.workers))
+	for _, worker := range lb.workers {
+		stats = append(stats, WorkerStats{
+			URL:          worker.URL,
+			Outstanding:  worker.outstanding,
+			Requests:     worker.requests,
+			Failures:     worker.failures,
+			Healthy:      !now.Before(worker.ejectedUntil),
+			EjectedUntil: worker.ejectedUntil,
+		})
+	}
+	return stats
+}
+
+// Close 停止健康检查
+func (lb *LoadBalancer) Close() {
+	lb.stopOnce.Do(func() {
+		close(lb.stop)
+	})
+}
@@ -0,0 +1,127 @@
+package rpccall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLoadBalancerRetriesOnAnotherWorkerAndEjects(t *testing.T) {
+	var badRequests, goodRequests int32
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&badRequests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != parseBlockDataPath {
+			t.Errorf("path = %s, want %s", r.URL.Path, parseBlockDataPath)
+		}
+		atomic.AddInt32(&goodRequests, 1)
+	}))
+	defer good.Close()
+
+	lb, err := NewLoadBalancer([]string{bad.URL, strings.TrimPrefix(good.URL, "http://")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 6; i++ {
+		if err := lb.Post([]string{"block"}); err != nil {
+			t.Fatalf("Post() #%d = %v, want retried on the healthy worker", i, err)
+		}
+	}
+
+	// 连续失败 3 次后剔除，之后不再发往该 worker
+	if got := atomic.LoadInt32(&badRequests); got != workerEjectConsecutive {
+		t.Errorf("bad worker requests = %d, want %d", got, workerEjectConsecutive)
+	}
+	if got := atomic.LoadInt32(&goodRequests); got != 6 {
+		t.Errorf("good worker requests = %d, want 6", got)
+	}
+	if stats := lb.Stats(); stats[0].Healthy || !stats[1].Healthy {
+		t.Errorf("stats = %+v, want bad worker ejected", stats)
+	}
+}
+
+func TestLoadBalancerDoesNotRetryClientErrors(t *testing.T) {
+	var requests int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	first := httptest.NewServer(handler)
+	defer first.Close()
+	second := httptest.NewServer(handler)
+	defer second.Close()
+
+	lb, err := NewLoadBalancer([]string{first.URL, second.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2*workerEjectConsecutive; i++ {
+		if err := lb.Post([]string{"malformed"}); err == nil {
+			t.Fatalf("Post() #%d = nil, want the 400 error", i)
+		}
+	}
+
+	// 每次只请求一个 worker，且不会因请求本身的错误剔除 worker
+	if got := atomic.LoadInt32(&requests); got != 2*workerEjectConsecutive {
+		t.Errorf("requests = %d, want %d", got, 2*workerEjectConsecutive)
+	}
+	for _, stats := range lb.Stats() {
+		if !stats.Healthy || stats.Failures != 0 {
+			t.Errorf("stats = %+v, want healthy worker without failures", stats)
+		}
+	}
+}
+
+func TestLoadBalancerPicksLeastOutstanding(t *testing.T) {
+	lb, err := NewLoadBalancer([]string{"http://a", "http://b", "http://c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := lb.acquire(nil)
+	second := lb.acquire(nil)
+	third := lb.acquire(nil)
+	if first == second || second == third || first == third {
+		t.Fatalf("acquire() = %s, %s, %s, want three different workers", first.URL, second.URL, third.URL)
+	}
+
+	lb.release(second, nil)
+	if got := lb.acquire(nil); got != second {
+		t.Errorf("acquire() = %s, want %s with no outstanding requests", got.URL, second.URL)
+	}
+}
+
+func TestLoadBalancerHealthCheck(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL
+	down.Close()
+
+	lb, err := NewLoadBalancer([]string{server.URL, downURL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lb.mu.Lock()
+	lb.eject(lb.workers[0])
+	lb.mu.Unlock()
+
+	// 有响应的 worker 恢复，无法连接的 worker 被剔除
+	lb.CheckHealth()
+	stats := lb.Stats()
+	if !stats[0].Healthy || stats[1].Healthy {
+		t.Errorf("stats = %+v, want first restored and second ejected", stats)
+	}
+
+	server.Close()
+	lb.CheckHealth()
+	if lb.Stats()[0].Healthy {
+		t.Errorf("closed worker still healthy after health check")
+	}
+}
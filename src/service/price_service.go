@@ -9,6 +9,7 @@ import (
 	ckdriver "github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/go-solana-parse/src/config"
 	"github.com/go-solana-parse/src/db/clickhouse"
+	"github.com/go-solana-parse/src/util"
 )
 
 // PriceCache 内存缓存结构（用于快速访问最近使用的价格）
@@ -120,7 +121,7 @@ func (ps *PriceService) GetTokenPriceAtBlock(tokenAddress string, blockHeight ui
 
 // BatchCalculateAndStorePrices 批量计算并存储SOL价格（用于历史数据预处理）
 func (ps *PriceService) BatchCalculateAndStorePrices(startBlock, endBlock uint64) error {
-	fmt.Printf("开始批量计算SOL价格，区块范围: %d - %d\n", startBlock, endBlock)
+	util.Logf("开始批量计算SOL价格，区块范围: %d - %d\n", startBlock, endBlock)
 
 	// 获取该范围内所有SOL-USDC交易
 	transactions, err := ps.historyDataDB.GetSOLTransactionsInRange(ps.clickhouseClient, startBlock, endBlock)
@@ -154,7 +155,7 @@ func (ps *PriceService) BatchCalculateAndStorePrices(startBlock, endBlock uint64
 		if err != nil {
 			return fmt.Errorf("批量插入SOL价格失败: %v", err)
 		}
-		fmt.Printf("成功插入 %d 条SOL价格记录\n", len(prices))
+		util.Logf("成功插入 %d 条SOL价格记录\n", len(prices))
 	}
 
 	return nil
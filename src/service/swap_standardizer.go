@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/parser"
+)
+
+// swap 被过滤的原因
+var (
+	ErrNoBaseToken       = errors.New("交易双方都不是基础代币")
+	ErrInvalidAmount     = errors.New("交易数量无效")
+	ErrBlacklistedToken  = errors.New("黑名单代币")
+	ErrBlacklistedWallet = errors.New("黑名单钱包")
+	ErrMEVBot            = errors.New("MEV 机器人")
+	ErrBelowMinAmount    = errors.New("交易金额低于最小值")
+)
+
+// SOLPriceProvider 提供 SOL 在指定区块高度的 USD 价格，PriceService 实现该接口
+type SOLPriceProvider interface {
+	GetSOLPriceAtBlock(blockHeight uint64) (float64, error)
+}
+
+// SwapStandardizer 将解析出的 swap 转换为 BUY/SELL 交易记录（对应 TS 版本 SolanaBlockDataHandler.handleBlockData）
+type SwapStandardizer struct {
+	prices       SOLPriceProvider
+	minUSDAmount float64
+}
+
+// NewSwapStandardizer 创建 swap 标准化器，最小交易金额取自 SNAP_SHOT_CONFIG
+func NewSwapStandardizer(prices SOLPriceProvider) *SwapStandardizer {
+	minUSDAmount, _ := model.SNAP_SHOT_CONFIG.MinTransactionAmount.Float64()
+	return &SwapStandardizer{
+		prices:       prices,
+		minUSDAmount: minUSDAmount,
+	}
+}
+
+// StandardizeTrades 转换一批 swap，跳过被过滤与无法定价的 swap，返回转换结果与各原因的跳过数
+func (s *SwapStandardizer) StandardizeTrades(trades []model.TradeInfo) ([]model.SwapTransaction, map[string]int) {
+	swaps := make([]model.SwapTransaction, 0, len(trades))
+	skipped := make(map[string]int)
+	for _, trade := range trades {
+		swap, err := s.Standardize(trade)
+		if err != nil {
+			skipped[skipReason(err)]++
+			continue
+		}
+		swaps = append(swaps, *swap)
+	}
+	return swaps, skipped
+}
+
+// Standardize 转换一笔 swap：区分代币与报价代币，推导交易方向，按报价代币的 USD 价格计算金额并应用过滤条件
+// 被过滤时返回 ErrNoBaseToken 等错误，获取 SOL 价格失败时返回对应错误
+func (s *SwapStandardizer) Standardize(trade model.TradeInfo) (*model.SwapTransaction, error) {
+	data, err := newTokenSwapFilterData(trade)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.IsBlacklistedToken(data.TokenAddress) {
+		return nil, ErrBlacklistedToken
+	}
+	if config.IsBlacklistedWallet(data.UserAddress) {
+		return nil, ErrBlacklistedWallet
+	}
+	if config.IsMEVBot(data.UserAddress) {
+		return nil, ErrMEVBot
+	}
+
+	quoteUSDPrice, err := s.quoteUSDPrice(data.QuoteAddress, data.BlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	data.USDPrice = data.QuotePrice * quoteUSDPrice
+	data.USDAmount = data.QuoteAmount * quoteUSDPrice
+	if data.USDAmount < s.minUSDAmount {
+		return nil, ErrBelowMinAmount
+	}
+
+	swap := newSwapTransaction(data, trade.IDX)
+	return &swap, nil
+}
+
+// quoteUSDPrice 报价代币的 USD 价格：稳定币为 1，SOL / WSOL 按区块高度查询
+func (s *SwapStandardizer) quoteUSDPrice(quoteAddress string, blockHeight uint64) (float64, error) {
+	if config.IsStableToken(quoteAddress) {
+		return 1, nil
+	}
+	price, err := s.prices.GetSOLPriceAtBlock(blockHeight)
+	if err != nil {
+		return 0, fmt.Errorf("获取区块 %d 的 SOL 价格失败: %v", blockHeight, err)
+	}
+	return price, nil
+}
+
+// newTokenSwapFilterData 选出代币与报价代币：报价代币为基础代币，双方都是基础代币时稳定币作为报价代币
+// 用户付出报价代币时为 BUY，收到报价代币时为 SELL
+func newTokenSwapFilterData(trade model.TradeInfo) (*model.TokenSwapFilterData, error) {
+	inIsBase, outIsBase := config.IsBaseToken(trade.TokenInMint), config.IsBaseToken(trade.TokenOutMint)
+	if !inIsBase && !outIsBase {
+		return nil, ErrNoBaseToken
+	}
+
+	isBuy := inIsBase
+	if inIsBase && outIsBase {
+		isBuy = config.IsStableToken(trade.TokenInMint) || !config.IsStableToken(trade.TokenOutMint)
+	}
+
+	data := &model.TokenSwapFilterData{
+		UserAddress:     trade.Signer,
+		PoolAddress:     trade.PoolAddress,
+		TxHash:          trade.Signature,
+		IsBuy:           isBuy,
+		BlockHeight:     trade.SlotNumber,
+		TransactionTime: trade.BlockTime,
+	}
+
+	tokenIn := parser.RawAmountToUI(trade.TokenInAmount, trade.TokenInDecimals)
+	tokenOut := parser.RawAmountToUI(trade.TokenOutAmount, trade.TokenOutDecimals)
+	if isBuy {
+		data.TokenAddress, data.TokenSymbol, data.TokenAmount = trade.TokenOutMint, trade.TokenOutSymbol, tokenOut
+		data.QuoteAddress, data.QuoteSymbol, data.QuoteAmount = trade.TokenInMint, trade.TokenInSymbol, tokenIn
+	} else {
+		data.TokenAddress, data.TokenSymbol, data.TokenAmount = trade.TokenInMint, trade.TokenInSymbol, tokenIn
+		data.QuoteAddress, data.QuoteSymbol, data.QuoteAmount = trade.TokenOutMint, trade.TokenOutSymbol, tokenOut
+	}
+	if data.TokenAmount <= 0 || data.QuoteAmount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if data.QuoteSymbol == "" {
+		data.QuoteSymbol = config.SOLANA_DEX_ADDRESS_TO_NAME[data.QuoteAddress]
+	}
+	data.QuotePrice = data.QuoteAmount / data.TokenAmount
+	return data, nil
+}
+
+// newSwapTransaction 生成最终交易记录，价格与金额按 TS 版本以字符串保存
+func newSwapTransaction(data *model.TokenSwapFilterData, idx string) model.SwapTransaction {
+	tradeType := TRADE_TYPE_SELL
+	if data.IsBuy {
+		tradeType = TRADE_TYPE_BUY
+	}
+	return model.SwapTransaction{
+		TxHash:          data.TxHash,
+		TransactionTime: data.TransactionTime,
+		WalletAddress:   data.UserAddress,
+		TokenAmount:     data.TokenAmount,
+		TokenSymbol:     data.TokenSymbol,
+		TokenAddress:    data.TokenAddress,
+		QuoteSymbol:     data.QuoteSymbol,
+		QuoteAmount:     data.QuoteAmount,
+		QuoteAddress:    data.QuoteAddress,
+		QuotePrice:      formatFloat(data.QuotePrice),
+		USDPrice:        formatFloat(data.USDPrice),
+		USDAmount:       formatFloat(data.USDAmount),
+		TradeType:       tradeType,
+		PoolAddress:     data.PoolAddress,
+		BlockHeight:     data.BlockHeight,
+		Idx:             idx,
+	}
+}
+
+// skipReason 跳过原因，价格错误统一归为一类
+func skipReason(err error) string {
+	for _, filtered := range []error{ErrNoBaseToken, ErrInvalidAmount, ErrBlacklistedToken, ErrBlacklistedWallet, ErrMEVBot, ErrBelowMinAmount} {
+		if errors.Is(err, filtered) {
+			return filtered.Error()
+		}
+	}
+	return "无法获取价格"
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+const (
+	testWallet = "5TLRz619uQoDEPtyUK2z4NLVMQF6xrV9hYPRFMXqbNRV"
+	testToken  = "F3QEA7LhaUmVVcPTdRCi62hAYd8bLwPbNgwbwPh6SE4A"
+)
+
+// fixedSOLPrice 固定 SOL 价格，price 为 0 时返回错误
+type fixedSOLPrice float64
+
+func (p fixedSOLPrice) GetSOLPriceAtBlock(blockHeight uint64) (float64, error) {
+	if p == 0 {
+		return 0, fmt.Errorf("no price at block %d", blockHeight)
+	}
+	return float64(p), nil
+}
+
+func newTestTrade(inMint, inAmount string, inDecimals uint8, outMint, outAmount string, outDecimals uint8) model.TradeInfo {
+	return model.TradeInfo{
+		Signature:        "sig",
+		Signer:           testWallet,
+		TokenInMint:      inMint,
+		TokenInAmount:    inAmount,
+		TokenInDecimals:  inDecimals,
+		TokenOutMint:     outMint,
+		TokenOutAmount:   outAmount,
+		TokenOutDecimals: outDecimals,
+		SlotNumber:       100,
+		BlockTime:        1700000000,
+		PoolAddress:      "pool",
+		IDX:              "2-0",
+	}
+}
+
+func TestSwapStandardizerBuyWithSOL(t *testing.T) {
+	standardizer := NewSwapStandardizer(fixedSOLPrice(150))
+
+	// 2 SOL 买入 1000 个代币
+	swap, err := standardizer.Standardize(newTestTrade(config.WSOL_ADDRESS, "2000000000", 9, testToken, "1000000000", 6))
+	if err != nil {
+		t.Fatalf("Standardize() error = %v", err)
+	}
+
+	if swap.TradeType != TRADE_TYPE_BUY || swap.TokenAddress != testToken || swap.TokenAmount != 1000 {
+		t.Errorf("token side = %s %s %v, want BUY %s 1000", swap.TradeType, swap.TokenAddress, swap.TokenAmount, testToken)
+	}
+	if swap.QuoteAddress != config.WSOL_ADDRESS || swap.QuoteSymbol != "WSOL" || swap.QuoteAmount != 2 {
+		t.Errorf("quote side = %s %s %v, want WSOL 2", swap.QuoteAddress, swap.QuoteSymbol, swap.QuoteAmount)
+	}
+	if swap.QuotePrice != "0.002" || swap.USDPrice != "0.3" || swap.USDAmount != "300" {
+		t.Errorf("prices = %s / %s / %s, want 0.002 / 0.3 / 300", swap.QuotePrice, swap.USDPrice, swap.USDAmount)
+	}
+	if swap.Idx != "2-0" || swap.BlockHeight != 100 || swap.WalletAddress != testWallet {
+		t.Errorf("swap = %+v", swap)
+	}
+}
+
+func TestSwapStandardizerSellForStable(t *testing.T) {
+	// 卖出 SOL 换 USDC 时 USDC 为报价代币；卖出代币换 USDC 不需要 SOL 价格
+	standardizer := NewSwapStandardizer(fixedSOLPrice(0))
+
+	swap, err := standardizer.Standardize(newTestTrade(testToken, "500000000", 6, config.USDC_ADDRESS, "25000000", 6))
+	if err != nil {
+		t.Fatalf("Standardize() error = %v", err)
+	}
+	if swap.TradeType != TRADE_TYPE_SELL || swap.TokenAmount != 500 || swap.QuoteAddress != config.USDC_ADDRESS || swap.USDAmount != "25" {
+		t.Errorf("swap = %+v, want SELL 500 tokens for 25 USDC", swap)
+	}
+
+	trade := newTestTrade(config.WSOL_ADDRESS, "1000000000", 9, config.USDC_ADDRESS, "150000000", 6)
+	data, err := newTokenSwapFilterData(trade)
+	if err != nil || data.IsBuy || data.QuoteAddress != config.USDC_ADDRESS {
+		t.Errorf("SOL -> USDC = %+v, %v, want SELL with USDC as quote", data, err)
+	}
+}
+
+func TestSwapStandardizerFilters(t *testing.T) {
+	standardizer := NewSwapStandardizer(fixedSOLPrice(150))
+	buy := func(mutate func(*model.TradeInfo)) model.TradeInfo {
+		trade := newTestTrade(config.WSOL_ADDRESS, "2000000000", 9, testToken, "1000000000", 6)
+		mutate(&trade)
+		return trade
+	}
+
+	cases := []struct {
+		name  string
+		trade model.TradeInfo
+		want  error
+	}{
+		{"no base token", buy(func(tr *model.TradeInfo) { tr.TokenInMint = "otherToken" }), ErrNoBaseToken},
+		{"below minimum", buy(func(tr *model.TradeInfo) { tr.TokenInAmount = "1000000" }), ErrBelowMinAmount},
+		{"blacklisted token", buy(func(tr *model.TradeInfo) { tr.TokenOutMint = config.BLACK_LIST_TOKEN[1] }), ErrBlacklistedToken},
+		{"blacklisted wallet", buy(func(tr *model.TradeInfo) { tr.Signer = config.WALLET_BLACKLIST[1] }), ErrBlacklistedWallet},
+		{"mev bot", buy(func(tr *model.TradeInfo) { tr.Signer = config.MEVBOT_ADDRESSES[0] }), ErrMEVBot},
+		{"zero amount", buy(func(tr *model.TradeInfo) { tr.TokenOutAmount = "0" }), ErrInvalidAmount},
+	}
+	for _, c := range cases {
+		if _, err := standardizer.Standardize(c.trade); !errors.Is(err, c.want) {
+			t.Errorf("%s: Standardize() error = %v, want %v", c.name, err, c.want)
+		}
+	}
+
+	trades := []model.TradeInfo{cases[0].trade, buy(func(*model.TradeInfo) {})}
+	swaps, skipped := NewSwapStandardizer(fixedSOLPrice(0)).StandardizeTrades(trades)
+	if len(swaps) != 0 || skipped[ErrNoBaseToken.Error()] != 1 || skipped["无法获取价格"] != 1 {
+		t.Errorf("StandardizeTrades() = %d swaps, skipped %v", len(swaps), skipped)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/go-solana-parse/src/db/clickhouse"
 	"github.com/go-solana-parse/src/db/mysql"
 	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
 	"github.com/shopspring/decimal"
 )
 
@@ -113,10 +114,10 @@ func (calc *UserReportCalculator) calculateBasicMetrics(userReport *mysql.UserRe
 		quotePrice := 1.0
 
 		if tx.QuoteAddress == config.SOL_ADDRESS || tx.QuoteAddress == config.WSOL_ADDRESS {
-			fmt.Printf("获取SOL价格: %d\n", tx.BlockHeight)
+			util.Logf("获取SOL价格: %d\n", tx.BlockHeight)
 			solPrice, err := calc.priceService.GetSOLPriceAtBlock(tx.BlockHeight)
 			if err != nil {
-				fmt.Printf("获取SOL价格失败: %v\n", err)
+				util.Logf("获取SOL价格失败: %v\n", err)
 			}
 			if err == nil && solPrice > 0 {
 				quotePrice = solPrice
@@ -152,7 +153,7 @@ func (calc *UserReportCalculator) calculateTokenPnL(transactions []*clickhouse.S
 		tokenAddr := tx.TokenAddress
 
 		if tokenAddr == "3LNJzpzLzobb9kghSwH1Ro1W3NPB4G1Vc9vTdj2P3Jij" {
-			fmt.Printf("代币地址: %s 交易类型: %s 交易数量: %f 交易价格: %f\n", tokenAddr, tx.TradeType, tx.TokenAmount, tx.QuoteAmount)
+			util.Logf("代币地址: %s 交易类型: %s 交易数量: %f 交易价格: %f\n", tokenAddr, tx.TradeType, tx.TokenAmount, tx.QuoteAmount)
 		}
 
 		if tokenMap[tokenAddr] == nil {
@@ -179,7 +180,7 @@ func (calc *UserReportCalculator) calculateTokenPnL(transactions []*clickhouse.S
 			if token.TotalBuyAmount > 0 {
 				token.AvgBuyPrice = token.TotalBuyValue / token.TotalBuyAmount
 				if token.TokenAddress == "3LNJzpzLzobb9kghSwH1Ro1W3NPB4G1Vc9vTdj2P3Jij" {
-					fmt.Printf("代币地址: %s 总买入价值: %f 总买入数量: %f 平均买入价格: %f\n tx.QuoteAmount: %f\n", token.TokenAddress, token.TotalBuyValue, token.TotalBuyAmount, token.AvgBuyPrice, tx.QuoteAmount)
+					util.Logf("代币地址: %s 总买入价值: %f 总买入数量: %f 平均买入价格: %f\n tx.QuoteAmount: %f\n", token.TokenAddress, token.TotalBuyValue, token.TotalBuyAmount, token.AvgBuyPrice, tx.QuoteAmount)
 				}
 			}
 
@@ -217,7 +218,7 @@ func (calc *UserReportCalculator) calculateTokenPnL(transactions []*clickhouse.S
 
 				realizedPnL := (sellPrice - token.AvgBuyPrice) * realizedSellTokenAmount
 				if token.TokenAddress == "3LNJzpzLzobb9kghSwH1Ro1W3NPB4G1Vc9vTdj2P3Jij" {
-					fmt.Printf("代币地址: %s 卖出价格: %f 卖出数量: %f 真实卖出数量: %f 平均买入价格: %f 当前持仓: %f 盈亏: %f\n", token.TokenAddress, sellPrice, tx.TokenAmount, realizedSellTokenAmount, token.AvgBuyPrice, token.CurrentHolding, realizedPnL)
+					util.Logf("代币地址: %s 卖出价格: %f 卖出数量: %f 真实卖出数量: %f 平均买入价格: %f 当前持仓: %f 盈亏: %f\n", token.TokenAddress, sellPrice, tx.TokenAmount, realizedSellTokenAmount, token.AvgBuyPrice, token.CurrentHolding, realizedPnL)
 				}
 				token.RealizedPnL += realizedPnL
 			}
@@ -255,7 +256,7 @@ func (calc *UserReportCalculator) calculatePnLMetrics(userReport *mysql.UserRepo
 	var lossLevel1, lossLevel2 int64                     // 0-50%, >50%
 
 	for tokenAddr, tokenData := range tokenPnLMap {
-		fmt.Printf("代币地址: %s 总盈亏: %f 当前价格: %f 平均买入价格: %f 平均卖出价格: %f 当前持仓: %f 总买入: %f 总卖出: %f\n", tokenAddr, tokenData.RealizedPnL, tokenData.UnrealizedPnL, tokenData.AvgBuyPrice, tokenData.AvgSellPrice, tokenData.CurrentHolding, tokenData.TotalBuyValue, tokenData.TotalSellValue)
+		util.Logf("代币地址: %s 总盈亏: %f 当前价格: %f 平均买入价格: %f 平均卖出价格: %f 当前持仓: %f 总买入: %f 总卖出: %f\n", tokenAddr, tokenData.RealizedPnL, tokenData.UnrealizedPnL, tokenData.AvgBuyPrice, tokenData.AvgSellPrice, tokenData.CurrentHolding, tokenData.TotalBuyValue, tokenData.TotalSellValue)
 		if tokenData.TotalBuyValue == 0 {
 			continue // 跳过没有买入记录的代币
 		}
@@ -271,7 +272,7 @@ func (calc *UserReportCalculator) calculatePnLMetrics(userReport *mysql.UserRepo
 
 		totalPnL := tokenData.RealizedPnL + tokenData.UnrealizedPnL
 
-		fmt.Printf("代币地址: %s 总盈亏: %f 当前价格: %f 平均买入价格: %f 当前持仓: %f 总买入: %f 总卖出: %f\n",
+		util.Logf("代币地址: %s 总盈亏: %f 当前价格: %f 平均买入价格: %f 当前持仓: %f 总买入: %f 总卖出: %f\n",
 			tokenAddr, totalPnL, currentPrice, tokenData.AvgBuyPrice, tokenData.CurrentHolding, tokenData.TotalBuyValue, tokenData.TotalSellValue)
 
 		// 统计盈亏代币数量
@@ -0,0 +1,50 @@
+package sink
+
+import (
+	"github.com/go-solana-parse/src/db/clickhouse"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/parser"
+	"github.com/go-solana-parse/src/service"
+	"github.com/go-solana-parse/src/util"
+)
+
+// ClickHouseSink 使用原生 Go 解析器解析区块，swap 标准化为 BUY/SELL 交易后经批量写入器写入 solana_history_data_new
+type ClickHouseSink struct {
+	parser       *parser.TransactionParser
+	standardizer *service.SwapStandardizer
+	writer       *clickhouse.HistoryDataWriter
+}
+
+// NewClickHouseSink 创建 ClickHouse sink，Close 时写入剩余数据并关闭 writer
+func NewClickHouseSink(standardizer *service.SwapStandardizer, writer *clickhouse.HistoryDataWriter) *ClickHouseSink {
+	return &ClickHouseSink{
+		parser:       parser.NewTransactionParser(nil),
+		standardizer: standardizer,
+		writer:       writer,
+	}
+}
+
+// Send 解析区块并写入 swap，等到写入完成后返回
+// 写入失败时返回错误，这批区块记为失败，重试时已写入的 swap 会被去重
+func (s *ClickHouseSink) Send(blocks []model.ParseBlockDataDenoReq) error {
+	results, err := parseBlocks(s.parser, blocks)
+	if err != nil {
+		return err
+	}
+
+	var trades []model.TradeInfo
+	for _, result := range results {
+		trades = append(trades, result.Trades...)
+	}
+
+	swaps, skipped := s.standardizer.StandardizeTrades(trades)
+	if len(skipped) > 0 && len(blocks) > 0 {
+		util.Logf("🔍 区块 %s - %s: 写入 %d 笔 swap，跳过 %v\n", blocks[0].BlockNum, blocks[len(blocks)-1].BlockNum, len(swaps), skipped)
+	}
+	return s.writer.WriteSwaps(swaps...)
+}
+
+// Close 写入剩余数据
+func (s *ClickHouseSink) Close() error {
+	return s.writer.Close()
+}
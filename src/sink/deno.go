@@ -0,0 +1,27 @@
+package sink
+
+import (
+	"github.com/go-solana-parse/src/model"
+	rpccall "github.com/go-solana-parse/src/rpc_call"
+)
+
+// DenoSink 将区块发送到 Deno 解析服务，由服务端解析并入库
+type DenoSink struct{}
+
+// NewDenoSink 创建 Deno 解析服务 sink
+func NewDenoSink() *DenoSink {
+	return &DenoSink{}
+}
+
+// Send 整批区块发送到一个 Deno worker
+func (s *DenoSink) Send(blocks []model.ParseBlockDataDenoReq) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	return rpccall.SendMultipleParseDataToDeno(blocks)
+}
+
+// Close 无需释放资源
+func (s *DenoSink) Close() error {
+	return nil
+}
@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/parser"
+)
+
+// NDJSONSink 使用原生 Go 解析器解析区块，每笔 swap 输出一行 JSON
+type NDJSONSink struct {
+	parser *parser.TransactionParser
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+}
+
+// NewNDJSONSink 创建写入 w 的 NDJSON sink，Close 时不关闭 w
+func NewNDJSONSink(w io.Writer) *NDJSONSink {
+	return &NDJSONSink{
+		parser: parser.NewTransactionParser(nil),
+		w:      bufio.NewWriter(w),
+	}
+}
+
+// NewFileSink 创建写入文件的 NDJSON sink，文件已存在时追加
+func NewFileSink(path string) (*NDJSONSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开输出文件失败: %v", err)
+	}
+	s := NewNDJSONSink(file)
+	s.closer = file
+	return s, nil
+}
+
+// Send 解析区块并写出其中的 swap，每批写完后刷新，保证已返回成功的数据已落盘
+func (s *NDJSONSink) Send(blocks []model.ParseBlockDataDenoReq) error {
+	results, err := parseBlocks(s.parser, blocks)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enc := json.NewEncoder(s.w)
+	for _, result := range results {
+		for _, trade := range result.Result.Trades {
+			if err := enc.Encode(trade); err != nil {
+				return err
+			}
+		}
+	}
+	return s.w.Flush()
+}
+
+// Close 刷新缓冲并关闭文件
+func (s *NDJSONSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.w.Flush()
+	if s.closer != nil {
+		if closeErr := s.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// parseBlocks 使用原生 Go 解析器解析区块，只返回解析成功的交易
+func parseBlocks(p *parser.TransactionParser, blocks []model.ParseBlockDataDenoReq) ([]model.ParseResult, error) {
+	var results []model.ParseResult
+	for i := range blocks {
+		slot, err := strconv.ParseUint(blocks[i].BlockNum, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("区块号无效: %s", blocks[i].BlockNum)
+		}
+		for _, result := range p.ParseBlock(&blocks[i].BlockData, slot) {
+			if result.State {
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,85 @@
+package sink
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/db"
+	"github.com/go-solana-parse/src/db/clickhouse"
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/service"
+	"github.com/go-solana-parse/src/util"
+)
+
+// 解析结果去向
+const (
+	SinkDeno       = "deno"       // 发送到 Deno 解析服务
+	SinkClickHouse = "clickhouse" // 原生 Go 解析后写入 ClickHouse
+	SinkFile       = "file"       // 原生 Go 解析后按 NDJSON 写入文件
+	SinkStdout     = "stdout"     // 原生 Go 解析后按 NDJSON 输出到标准输出
+)
+
+// ParseSink 解析结果去向，扫描、重试与跟随链上区块共用；实现需支持多个 worker 并发调用
+type ParseSink interface {
+	// Send 处理一批区块，返回错误时这批区块都视为失败
+	Send(blocks []model.ParseBlockDataDenoReq) error
+	// Close 刷新并释放资源
+	Close() error
+}
+
+// Validate 校验 sink 类型，file 需要指定输出路径
+func Validate(kind, path string) error {
+	switch kind {
+	case SinkDeno, SinkClickHouse, SinkStdout:
+		return nil
+	case SinkFile:
+		if path == "" {
+			return fmt.Errorf("sink 为 %s 时需要指定输出文件路径", SinkFile)
+		}
+		return nil
+	}
+	return fmt.Errorf("不支持的 sink: %s（可用 %s、%s、%s、%s）", kind, SinkDeno, SinkClickHouse, SinkFile, SinkStdout)
+}
+
+// NewParseSink 根据类型创建 sink，path 为 file 类型的输出路径
+// clickhouse 类型在 ClickHouse 连接尚未初始化时按配置文件建立连接
+func NewParseSink(kind, path string) (ParseSink, error) {
+	if err := Validate(kind, path); err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case SinkClickHouse:
+		if db.ClickHouseClient == nil {
+			if err := db.InitClickHouseV2(); err != nil {
+				return nil, fmt.Errorf("初始化 ClickHouse 失败: %v", err)
+			}
+		}
+		standardizer := service.NewSwapStandardizer(service.NewPriceService(db.ClickHouseClient))
+		return NewClickHouseSink(standardizer, clickhouse.NewHistoryDataWriter(db.ClickHouseClient, historyDataWriterConfig())), nil
+	case SinkFile:
+		return NewFileSink(path)
+	case SinkStdout:
+		return NewNDJSONSink(os.Stdout), nil
+	}
+	return NewDenoSink(), nil
+}
+
+// historyDataWriterConfig 根据配置文件 clickhouse 段生成写入参数
+func historyDataWriterConfig() clickhouse.HistoryDataWriterConfig {
+	writerConfig := clickhouse.DefaultHistoryDataWriterConfig()
+	cfg := config.SvcConfig.ClickHouse
+	if cfg.WriteBatchSize > 0 {
+		writerConfig.BatchSize = cfg.WriteBatchSize
+	}
+	if cfg.FlushInterval != "" {
+		if interval, err := time.ParseDuration(cfg.FlushInterval); err == nil && interval > 0 {
+			writerConfig.FlushInterval = interval
+		} else {
+			util.Logf("⚠️ flush_interval 配置无效: %s，使用 %v\n", cfg.FlushInterval, writerConfig.FlushInterval)
+		}
+	}
+	return writerConfig
+}
@@ -0,0 +1,61 @@
+package sink
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+func TestValidate(t *testing.T) {
+	for _, kind := range []string{SinkDeno, SinkClickHouse, SinkStdout} {
+		if err := Validate(kind, ""); err != nil {
+			t.Errorf("Validate(%s) = %v", kind, err)
+		}
+	}
+	if err := Validate(SinkFile, ""); err == nil {
+		t.Errorf("Validate(file) without path = nil, want error")
+	}
+	if err := Validate("kafka", ""); err == nil {
+		t.Errorf("Validate(kafka) = nil, want error")
+	}
+}
+
+func TestNDJSONSinkRejectsInvalidBlockNum(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewNDJSONSink(&buf)
+	if err := s.Send([]model.ParseBlockDataDenoReq{{BlockNum: "abc"}}); err == nil {
+		t.Errorf("Send() with invalid block number = nil, want error")
+	}
+	if err := s.Send([]model.ParseBlockDataDenoReq{{BlockNum: "1"}}); err != nil {
+		t.Errorf("Send() empty block = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("output = %q, want empty", buf.String())
+	}
+}
+
+func TestFileSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trades.ndjson")
+	if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewFileSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != "{}\n" {
+		t.Errorf("content = %q, %v, want existing content kept", content, err)
+	}
+}
@@ -0,0 +1,165 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-solana-parse/src/model"
+	"golang.org/x/time/rate"
+)
+
+// AIMD 限流参数
+const (
+	adaptiveDecreaseFactor   = 0.5              // 被限流时速率乘以该系数
+	adaptiveDecreaseCooldown = time.Second      // 并发请求同时被限流时只降一次
+	adaptiveIncreaseInterval = 5 * time.Second  // 持续健康时每隔该时长提升一次
+	adaptiveIncreaseSteps    = 20               // 每次提升上限速率的 1/20
+	adaptiveMinRate          = 1.0              // 速率下限（请求/秒）
+	defaultRetryAfter        = 1 * time.Second  // 未返回 Retry-After 时的暂停时长
+	maxRetryAfter            = 60 * time.Second // Retry-After 上限，防止异常值长时间阻塞
+)
+
+// RPCErrorRateLimited 部分节点在 JSON-RPC 错误中返回的限流错误码
+const RPCErrorRateLimited = -32429
+
+// AdaptiveLimiter AIMD 自适应限流：被限流时速率减半并暂停到 Retry-After，持续健康时逐步恢复到上限
+type AdaptiveLimiter struct {
+	limiter *rate.Limiter
+	maxRate float64
+	step    float64
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+	lastChange  time.Time
+}
+
+// NewAdaptiveLimiter 创建自适应限流器，初始速率为上限 maxRate
+func NewAdaptiveLimiter(maxRate float64, burst int) *AdaptiveLimiter {
+	if maxRate < adaptiveMinRate {
+		maxRate = adaptiveMinRate
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	step := maxRate / adaptiveIncreaseSteps
+	if step < adaptiveMinRate {
+		step = adaptiveMinRate
+	}
+	return &AdaptiveLimiter{
+		limiter: rate.NewLimiter(rate.Limit(maxRate), burst),
+		maxRate: maxRate,
+		step:    step,
+	}
+}
+
+// Wait 等待暂停结束并获取令牌
+func (l *AdaptiveLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	pause := time.Until(l.pausedUntil)
+	l.mu.Unlock()
+
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return l.limiter.Wait(ctx)
+}
+
+// OnThrottled 被限流：乘性降低速率，并在 retryAfter 内暂停发送
+func (l *AdaptiveLimiter) OnThrottled(retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+	if retryAfter > maxRetryAfter {
+		retryAfter = maxRetryAfter
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if until := now.Add(retryAfter); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	if now.Sub(l.lastChange) < adaptiveDecreaseCooldown {
+		return
+	}
+
+	current := float64(l.limiter.Limit())
+	next := current * adaptiveDecreaseFactor
+	if next < adaptiveMinRate {
+		next = adaptiveMinRate
+	}
+	l.limiter.SetLimitAt(now, rate.Limit(next))
+	l.lastChange = now
+}
+
+// OnSuccess 请求未被限流：距上次调整超过间隔时加性提升速率
+func (l *AdaptiveLimiter) OnSuccess() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	current := float64(l.limiter.Limit())
+	if current >= l.maxRate || now.Sub(l.lastChange) < adaptiveIncreaseInterval {
+		return
+	}
+
+	next := current + l.step
+	if next > l.maxRate {
+		next = l.maxRate
+	}
+	l.limiter.SetLimitAt(now, rate.Limit(next))
+	l.lastChange = now
+}
+
+// Rate 当前速率（请求/秒）
+func (l *AdaptiveLimiter) Rate() float64 {
+	return float64(l.limiter.Limit())
+}
+
+// IsRateLimited 判断错误是否表示被节点限流（HTTP 429 或 JSON-RPC 限流错误），并返回建议的等待时长
+func IsRateLimited(err error) (bool, time.Duration) {
+	var slotErr *SlotError
+	if errors.As(err, &slotErr) && slotErr.Kind == SlotErrorRateLimited {
+		return true, slotErr.RetryAfter
+	}
+
+	var rpcErr *model.RPCError
+	if !errors.As(err, &rpcErr) {
+		return false, 0
+	}
+	if rpcErr.Code == RPCErrorRateLimited || rpcErr.Code == http.StatusTooManyRequests {
+		return true, 0
+	}
+	message := strings.ToLower(rpcErr.Message)
+	return strings.Contains(message, "rate limit") || strings.Contains(message, "too many requests"), 0
+}
+
+// parseRetryAfter 解析 Retry-After 响应头（秒数或 HTTP 日期），无法解析时返回 0
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
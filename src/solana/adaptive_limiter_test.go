@@ -0,0 +1,123 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+func TestAdaptiveLimiterDecreasesAndRecovers(t *testing.T) {
+	limiter := NewAdaptiveLimiter(100, 10)
+
+	limiter.OnThrottled(0)
+	if got := limiter.Rate(); got != 50 {
+		t.Fatalf("rate after throttle = %v, want 50", got)
+	}
+	// 冷却期内的并发限流不重复降速
+	limiter.OnThrottled(0)
+	if got := limiter.Rate(); got != 50 {
+		t.Fatalf("rate after second throttle = %v, want 50", got)
+	}
+	if pause := time.Until(limiter.pausedUntil); pause <= 0 || pause > defaultRetryAfter {
+		t.Errorf("pause = %v, want up to %v", pause, defaultRetryAfter)
+	}
+
+	// 未到提升间隔时保持不变
+	limiter.OnSuccess()
+	if got := limiter.Rate(); got != 50 {
+		t.Fatalf("rate before increase interval = %v, want 50", got)
+	}
+	limiter.lastChange = time.Now().Add(-adaptiveIncreaseInterval)
+	limiter.OnSuccess()
+	if got := limiter.Rate(); got != 55 {
+		t.Fatalf("rate after increase = %v, want 55", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":                              0,
+		"3":                             3 * time.Second,
+		"-1":                            0,
+		"Wed, 01 Jan 2025 00:00:10 GMT": 10 * time.Second,
+		"soon":                          0,
+	}
+	for value, want := range cases {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&SlotError{Kind: SlotErrorRateLimited, StatusCode: 429}, true},
+		{&SlotError{Kind: SlotErrorRPC, Code: RPCErrorRateLimited, Err: &model.RPCError{Code: RPCErrorRateLimited, Message: "rate limited"}}, true},
+		{&model.RPCError{Code: -32005, Message: "Too many requests for a specific RPC call"}, true},
+		{&SlotError{Kind: SlotErrorRPC, Code: -32004, Err: &model.RPCError{Code: -32004, Message: "Block not available"}}, false},
+		{&SlotError{Kind: SlotErrorTransport, Err: fmt.Errorf("timeout")}, false},
+	}
+	for _, c := range cases {
+		if got, _ := IsRateLimited(c.err); got != c.want {
+			t.Errorf("IsRateLimited(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestBatchRPCFetcherBacksOffOnTooManyRequests(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		first := calls == 1
+		mu.Unlock()
+		if first {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		var requests []model.RPCRequest
+		json.NewDecoder(r.Body).Decode(&requests)
+		responses := make([]model.GetBlockResponse, len(requests))
+		for i, request := range requests {
+			responses[i] = model.GetBlockResponse{JSONRPC: "2.0", ID: request.ID, Result: &model.Block{}}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	pool, err := NewEndpointPool([]config.EndpointConfig{{URL: server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetcher := NewBatchRPCFetcher(&BatchRPCConfig{MaxBatchSize: 5, MaxRequestsPerSecond: 100, BurstCapacity: 10, RetryAttempts: 1})
+	fetcher.pool = pool
+
+	start := time.Now()
+	blocks, slotErrors := CollectBlocks(context.Background(), fetcher, []uint64{1, 2, 3})
+	if len(blocks) != 3 || len(slotErrors) != 0 {
+		t.Fatalf("blocks = %d, errors = %v, want all slots after retry", len(blocks), slotErrors)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("elapsed = %v, want retry to wait for Retry-After", elapsed)
+	}
+
+	stats := fetcher.GetStats()
+	if stats.ThrottledBatches != 1 || stats.CurrentRate != 50 {
+		t.Errorf("stats = %+v, want one throttled batch and rate halved to 50", stats)
+	}
+}
@@ -1,62 +1,43 @@
 package solana
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
-	"github.com/go-solana-parse/src/config"
 	"github.com/go-solana-parse/src/model"
-	"golang.org/x/time/rate"
+	"github.com/go-solana-parse/src/util"
 )
 
-// BatchRPCRequest represents a single request in a batch
-type BatchRPCRequest struct {
-	JSONRPC string        `json:"jsonrpc"`
-	ID      string        `json:"id"`
-	Method  string        `json:"method"`
-	Params  []interface{} `json:"params"`
-}
-
-// BatchRPCResponse represents a single response in a batch
-type BatchRPCResponse struct {
-	JSONRPC string          `json:"jsonrpc"`
-	ID      string          `json:"id"`
-	Result  interface{}     `json:"result"`
-	Error   *model.RPCError `json:"error,omitempty"`
-}
-
-// BatchBlockResult represents the result of a batch block fetch
-type BatchBlockResult struct {
-	Slot     uint64
-	Block    model.Block
-	Error    error
-	Duration time.Duration
-}
+// MaxRPCBatchSize is the largest number of getBlock calls sent in one JSON-RPC batch request
+const MaxRPCBatchSize = 50
 
 // BatchRPCConfig holds configuration for batch RPC requests
 type BatchRPCConfig struct {
+	APIKey               string        // Helius API key, used when solana.endpoints is not configured
+	Commitment           string        // confirmed / finalized, empty for the node default
 	MaxBatchSize         int           // Maximum number of requests per batch
-	BatchTimeout         time.Duration // Timeout for each batch request
-	MaxRequestsPerSecond int           // Rate limiting
+	MaxConcurrentBatches int           // Number of batches in flight at the same time
+	BatchTimeout         time.Duration // Timeout for a whole batch including retries, 0 for no extra timeout
+	RequestTimeout       time.Duration // Timeout for a single getBlock batch request to one endpoint, 0 for no extra timeout
+	MaxRequestsPerSecond int           // Upper bound of the adaptive rate limit across all endpoints, 0 to rely on per-endpoint limits only
 	BurstCapacity        int           // Burst capacity for rate limiter
-	HTTPPoolSize         int           // HTTP connection pool size
-	RetryAttempts        int           // Number of retry attempts
+	RetryAttempts        int           // Number of retry attempts for slots that failed transiently
 	RetryDelay           time.Duration // Delay between retries
+
+	// TransactionFilter drops transactions while the response is being decoded, nil keeps all of them
+	TransactionFilter TransactionFilter
 }
 
 // DefaultBatchRPCConfig returns optimized default configuration for batch RPC
 func DefaultBatchRPCConfig() *BatchRPCConfig {
 	return &BatchRPCConfig{
 		MaxBatchSize:         50,               // 50 blocks per batch request
+		MaxConcurrentBatches: 15,               // 优化并发数量
 		BatchTimeout:         60 * time.Second, // 60 seconds per batch
 		MaxRequestsPerSecond: 100,              // Conservative rate limit for batch requests
 		BurstCapacity:        20,               // Allow bursts
-		HTTPPoolSize:         100,              // HTTP connections
 		RetryAttempts:        3,                // Retry attempts
 		RetryDelay:           1 * time.Second,  // Retry delay
 	}
@@ -66,22 +47,25 @@ func DefaultBatchRPCConfig() *BatchRPCConfig {
 func HighPerformanceBatchRPCConfig() *BatchRPCConfig {
 	return &BatchRPCConfig{
 		MaxBatchSize:         50,                     // 优化批次大小
+		MaxConcurrentBatches: 30,                     // 提高并发批次
 		BatchTimeout:         30 * time.Second,       // 30 second timeout
 		MaxRequestsPerSecond: 300,                    // 提高速率限制
 		BurstCapacity:        100,                    // 提高突发容量
-		HTTPPoolSize:         300,                    // 提高连接池
 		RetryAttempts:        3,                      // 更多重试
 		RetryDelay:           300 * time.Millisecond, // 平衡重试速度
 	}
 }
 
-// BatchRPCFetcher provides high-performance batch RPC functionality
+// BatchRPCFetcher is the batch JSON-RPC BlockSource: slots are split into getBlock batches,
+// routed through the shared endpoint pool and HTTP client, and retried on transient failures
 type BatchRPCFetcher struct {
 	config      *BatchRPCConfig
-	rateLimiter *rate.Limiter
+	pool        *EndpointPool
+	rateLimiter *AdaptiveLimiter // nil when MaxRequestsPerSecond is 0
 	httpClient  *http.Client
-	stats       *BatchRPCStats
-	mu          sync.RWMutex
+
+	statsMu sync.Mutex
+	stats   BatchRPCStats
 }
 
 // BatchRPCStats tracks batch RPC performance metrics
@@ -94,10 +78,11 @@ type BatchRPCStats struct {
 	FailedBlocks      int64
 	TotalRetries      int64
 	TotalDataFetched  int64
+	ThrottledBatches  int64   // Batches rejected by HTTP 429 or a JSON-RPC rate-limit error
+	CurrentRate       float64 // Current adaptive rate limit in requests/sec, 0 when unlimited
 	AverageBatchSize  float64
 	StartTime         time.Time
 	EndTime           time.Time
-	mu                sync.RWMutex
 }
 
 // NewBatchRPCFetcher creates a new optimized batch RPC fetcher
@@ -105,298 +90,203 @@ func NewBatchRPCFetcher(config *BatchRPCConfig) *BatchRPCFetcher {
 	if config == nil {
 		config = DefaultBatchRPCConfig()
 	}
-
-	// Create rate limiter
-	rateLimiter := rate.NewLimiter(rate.Limit(config.MaxRequestsPerSecond), config.BurstCapacity)
-
-	// Configure HTTP client for batch requests
-	transport := &http.Transport{
-		MaxIdleConns:        config.HTTPPoolSize,
-		MaxIdleConnsPerHost: config.HTTPPoolSize / 2, // 增加每主机连接数
-		MaxConnsPerHost:     config.HTTPPoolSize,     // 最大化连接数
-		IdleConnTimeout:     30 * time.Second,        // 减少空闲超时
-		DisableCompression:  false,
-		DisableKeepAlives:   false,
-		WriteBufferSize:     256 * 1024, // 更大的写缓冲区
-		ReadBufferSize:      256 * 1024, // 更大的读缓冲区
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = 1
+	}
+	if config.MaxConcurrentBatches <= 0 {
+		config.MaxConcurrentBatches = 1
 	}
 
-	httpClient := &http.Client{
-		Transport: transport,
-		Timeout:   config.BatchTimeout,
+	var rateLimiter *AdaptiveLimiter
+	if config.MaxRequestsPerSecond > 0 {
+		rateLimiter = NewAdaptiveLimiter(float64(config.MaxRequestsPerSecond), config.BurstCapacity)
 	}
 
 	return &BatchRPCFetcher{
 		config:      config,
+		pool:        endpointPoolFor(config.APIKey),
 		rateLimiter: rateLimiter,
-		httpClient:  httpClient,
-		stats:       &BatchRPCStats{StartTime: time.Now()},
+		httpClient:  getHighPerfClient(),
+		stats:       BatchRPCStats{StartTime: time.Now()},
 	}
 }
 
-// FetchBlocksBatch fetches multiple blocks using concurrent batch JSON-RPC requests
-func (f *BatchRPCFetcher) FetchBlocksBatch(ctx context.Context, startSlot uint64, count int) ([]BatchBlockResult, error) {
-	f.stats.mu.Lock()
-	f.stats.StartTime = time.Now()
-	f.stats.TotalBlocks = int64(count)
-	f.stats.mu.Unlock()
-
-	// Calculate number of batches
-	numBatches := (count + f.config.MaxBatchSize - 1) / f.config.MaxBatchSize
+// FetchBlocks fetches slots with up to MaxConcurrentBatches concurrent batch requests.
+// Results are emitted per batch, so their order follows batch completion rather than slots
+func (f *BatchRPCFetcher) FetchBlocks(ctx context.Context, slots []uint64) <-chan model.BlockResult {
+	out := make(chan model.BlockResult, f.config.MaxBatchSize)
+	batches := make(chan []uint64)
 
-	// Create channels for batch jobs and results
-	batchJobs := make(chan []uint64, numBatches)
-	results := make(chan []BatchBlockResult, numBatches)
-
-	// Start concurrent workers
-	const maxConcurrentBatches = 15 // 优化并发数量
 	var wg sync.WaitGroup
-
-	for i := 0; i < maxConcurrentBatches && i < numBatches; i++ {
+	for i := 0; i < f.config.MaxConcurrentBatches; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for slots := range batchJobs {
-				batchResults, err := f.processBatchWithRetry(ctx, slots)
-				if err != nil {
-					// Send empty results on error
-					results <- []BatchBlockResult{}
-				} else {
-					results <- batchResults
+			for batch := range batches {
+				for _, result := range f.processBatchWithRetry(ctx, batch) {
+					select {
+					case out <- result:
+					case <-ctx.Done():
+						return
+					}
 				}
 			}
 		}()
 	}
 
-	// Send batch jobs
 	go func() {
-		defer close(batchJobs)
-		for processed := 0; processed < count; processed += f.config.MaxBatchSize {
-			batchEnd := processed + f.config.MaxBatchSize
-			if batchEnd > count {
-				batchEnd = count
+		defer close(batches)
+		for i := 0; i < len(slots); i += f.config.MaxBatchSize {
+			end := i + f.config.MaxBatchSize
+			if end > len(slots) {
+				end = len(slots)
 			}
-
-			batchCount := batchEnd - processed
-			batchStartSlot := startSlot + uint64(processed)
-
-			slots := make([]uint64, batchCount)
-			for i := 0; i < batchCount; i++ {
-				slots[i] = batchStartSlot + uint64(i)
+			select {
+			case batches <- slots[i:end]:
+			case <-ctx.Done():
+				return
 			}
-
-			batchJobs <- slots
 		}
 	}()
 
-	// Collect results with progress tracking
-	var allResults []BatchBlockResult
-	processedBatches := 0
-
 	go func() {
 		wg.Wait()
-		close(results)
+		close(out)
 	}()
 
-	for batchResults := range results {
-		allResults = append(allResults, batchResults...)
-		processedBatches++
-
-		f.stats.mu.Lock()
-		f.stats.TotalBatches++
-		f.stats.mu.Unlock()
-
-		// Simple progress log
-		elapsed := time.Since(f.stats.StartTime)
-		successCount := 0
-		for _, r := range allResults {
-			if r.Error == nil {
-				successCount++
-			}
-		}
-
-		blocksProcessed := len(allResults)
-		if blocksProcessed > 0 {
-			successRate := float64(successCount) / float64(blocksProcessed) * 100
-			fmt.Printf("Progress: %d/%d blocks | Time: %v | Success: %.1f%%\n",
-				blocksProcessed, count, elapsed.Round(time.Second), successRate)
-		}
-	}
+	return out
+}
 
-	f.stats.mu.Lock()
-	f.stats.EndTime = time.Now()
-	if f.stats.TotalBatches > 0 {
-		f.stats.AverageBatchSize = float64(f.stats.TotalBlocks) / float64(f.stats.TotalBatches)
+// processBatchWithRetry fetches one batch and retries slots that failed transiently.
+// Skipped slots are permanent and are returned without retrying
+func (f *BatchRPCFetcher) processBatchWithRetry(ctx context.Context, slots []uint64) []model.BlockResult {
+	if f.config.BatchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.config.BatchTimeout)
+		defer cancel()
 	}
-	f.stats.mu.Unlock()
 
-	return allResults, nil
-}
-
-// processBatchWithRetry processes a batch of slots with retry logic
-func (f *BatchRPCFetcher) processBatchWithRetry(ctx context.Context, slots []uint64) ([]BatchBlockResult, error) {
-	var results []BatchBlockResult
-	var err error
+	blocks := make(map[uint64]*model.Block)
+	slotErrors := make(map[uint64]error)
+	remaining := slots
 
-	for attempt := 0; attempt <= f.config.RetryAttempts; attempt++ {
+	for attempt := 0; attempt <= f.config.RetryAttempts && len(remaining) > 0; attempt++ {
 		if attempt > 0 {
-			f.stats.mu.Lock()
+			f.statsMu.Lock()
 			f.stats.TotalRetries++
-			f.stats.mu.Unlock()
+			f.statsMu.Unlock()
 
 			// Wait before retry
 			select {
 			case <-ctx.Done():
-				return results, ctx.Err()
 			case <-time.After(f.config.RetryDelay):
 			}
 		}
 
 		// Wait for rate limiter (one request per batch)
-		if err = f.rateLimiter.Wait(ctx); err != nil {
-			continue
+		if err := f.waitRateLimit(ctx); err != nil {
+			for _, slot := range remaining {
+				slotErrors[slot] = err
+			}
+			break
 		}
 
-		// Process the batch
-		results, err = f.processBatch(ctx, slots)
-		if err == nil {
-			break // Success, no need to retry
+		batchBlocks, batchErrors := fetchBatchWithFailover(ctx, f.pool, remaining, f.httpClient, f.config.Commitment, f.config.TransactionFilter, f.config.RequestTimeout)
+		f.adjustRateLimit(batchErrors)
+
+		var retry []uint64
+		for _, slot := range remaining {
+			if block, ok := batchBlocks[slot]; ok {
+				blocks[slot] = block
+				delete(slotErrors, slot)
+				continue
+			}
+			err := batchErrors[slot]
+			slotErrors[slot] = err
+			if IsSlotSkipped(err) {
+				continue
+			}
+			retry = append(retry, slot)
 		}
+		remaining = retry
 	}
 
-	return results, err
-}
-
-// processBatch sends a single batch JSON-RPC request for multiple blocks
-func (f *BatchRPCFetcher) processBatch(ctx context.Context, slots []uint64) ([]BatchBlockResult, error) {
-	startTime := time.Now()
-
-	// Create batch request
-	batchRequest := make([]BatchRPCRequest, len(slots))
-	for i, slot := range slots {
-		batchRequest[i] = BatchRPCRequest{
-			JSONRPC: "2.0",
-			ID:      fmt.Sprintf("block-%d", slot),
-			Method:  "getBlock",
-			Params: []interface{}{
-				slot,
-				map[string]interface{}{
-					"encoding":                       "json",
-					"transactionDetails":             "full",
-					"rewards":                        true,
-					"maxSupportedTransactionVersion": 0,
-				},
-			},
+	results := make([]model.BlockResult, 0, len(slots))
+	transactions := 0
+	for _, slot := range slots {
+		if block, ok := blocks[slot]; ok {
+			results = append(results, model.BlockResult{Slot: slot, Block: block})
+			transactions += len(block.Transactions)
+			continue
 		}
+		results = append(results, model.BlockResult{Slot: slot, Error: slotErrors[slot]})
 	}
 
-	// Convert to JSON
-	requestData, err := json.Marshal(batchRequest)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal batch request: %w", err)
+	f.statsMu.Lock()
+	f.stats.TotalBatches++
+	f.stats.TotalBlocks += int64(len(slots))
+	f.stats.SuccessfulBlocks += int64(len(blocks))
+	f.stats.FailedBlocks += int64(len(slots) - len(blocks))
+	f.stats.TotalDataFetched += int64(transactions)
+	if len(blocks) == len(slots) {
+		f.stats.SuccessfulBatches++
+	} else {
+		f.stats.FailedBatches++
 	}
+	f.stats.AverageBatchSize = float64(f.stats.TotalBlocks) / float64(f.stats.TotalBatches)
+	f.stats.EndTime = time.Now()
+	f.statsMu.Unlock()
 
-	// Create HTTP request
-	rpcURL := config.SvcConfig.Solana.RpcUrl
-	if rpcURL == "" {
-		return nil, fmt.Errorf("Solana RPC URL not configured")
-	}
+	return results
+}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", rpcURL, bytes.NewBuffer(requestData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+// adjustRateLimit feeds the batch outcome to the adaptive limiter: any rate-limited slot
+// slows the fetcher down and pauses it for the longest Retry-After, otherwise the rate recovers
+func (f *BatchRPCFetcher) adjustRateLimit(slotErrors map[uint64]error) {
+	throttled := false
+	var retryAfter time.Duration
+	for _, err := range slotErrors {
+		if limited, wait := IsRateLimited(err); limited {
+			throttled = true
+			if wait > retryAfter {
+				retryAfter = wait
+			}
+		}
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	// Send request
-	resp, err := f.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	if throttled {
+		f.statsMu.Lock()
+		f.stats.ThrottledBatches++
+		f.statsMu.Unlock()
 	}
-	defer resp.Body.Close()
-
-	// Check HTTP status
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP request failed with status: %d", resp.StatusCode)
+	if f.rateLimiter == nil {
+		return
 	}
-
-	// Parse batch response
-	var batchResponse []BatchRPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&batchResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode batch response: %w", err)
+	if throttled {
+		f.rateLimiter.OnThrottled(retryAfter)
+		util.Logf("🐢 批量请求被限流，速率降至 %.1f/s，暂停 %v\n", f.rateLimiter.Rate(), retryAfter)
+		return
 	}
+	f.rateLimiter.OnSuccess()
+}
 
-	// Process responses
-	results := make([]BatchBlockResult, 0, len(slots))
-	duration := time.Since(startTime)
-
-	for _, slot := range slots {
-		result := BatchBlockResult{
-			Slot:     slot,
-			Duration: duration,
-		}
-
-		// Find corresponding response
-		found := false
-		for _, resp := range batchResponse {
-			if resp.ID == fmt.Sprintf("block-%d", slot) {
-				found = true
-				if resp.Error != nil {
-					result.Error = fmt.Errorf("RPC error: code=%d, message=%s", resp.Error.Code, resp.Error.Message)
-					f.stats.mu.Lock()
-					f.stats.FailedBlocks++
-					f.stats.mu.Unlock()
-				} else if resp.Result == nil {
-					result.Error = fmt.Errorf("block %d not found", slot)
-					f.stats.mu.Lock()
-					f.stats.FailedBlocks++
-					f.stats.mu.Unlock()
-				} else {
-					// Convert result to block struct
-					resultBytes, err := json.Marshal(resp.Result)
-					if err != nil {
-						result.Error = fmt.Errorf("failed to marshal result: %w", err)
-						f.stats.mu.Lock()
-						f.stats.FailedBlocks++
-						f.stats.mu.Unlock()
-					} else {
-						if err := json.Unmarshal(resultBytes, &result.Block); err != nil {
-							result.Error = fmt.Errorf("failed to unmarshal block data: %w", err)
-							f.stats.mu.Lock()
-							f.stats.FailedBlocks++
-							f.stats.mu.Unlock()
-						} else {
-							f.stats.mu.Lock()
-							f.stats.SuccessfulBlocks++
-							f.stats.TotalDataFetched += int64(len(result.Block.Transactions))
-							f.stats.mu.Unlock()
-						}
-					}
-				}
-				break
-			}
-		}
-
-		if !found {
-			result.Error = fmt.Errorf("no response found for block %d", slot)
-			f.stats.mu.Lock()
-			f.stats.FailedBlocks++
-			f.stats.mu.Unlock()
-		}
-
-		results = append(results, result)
+// waitRateLimit waits for the fetcher-wide rate limiter if one is configured
+func (f *BatchRPCFetcher) waitRateLimit(ctx context.Context) error {
+	if f.rateLimiter == nil {
+		return ctx.Err()
 	}
-
-	return results, nil
+	return f.rateLimiter.Wait(ctx)
 }
 
 // GetStats returns current batch RPC performance statistics
 func (f *BatchRPCFetcher) GetStats() BatchRPCStats {
-	f.stats.mu.RLock()
-	defer f.stats.mu.RUnlock()
+	f.statsMu.Lock()
+	defer f.statsMu.Unlock()
 
-	stats := *f.stats
+	stats := f.stats
+	if f.rateLimiter != nil {
+		stats.CurrentRate = f.rateLimiter.Rate()
+	}
 	if stats.EndTime.IsZero() {
 		stats.EndTime = time.Now()
 	}
@@ -408,10 +298,16 @@ func (f *BatchRPCFetcher) GetStats() BatchRPCStats {
 func (f *BatchRPCFetcher) PrintDetailedBatchStats() {
 	stats := f.GetStats()
 	duration := stats.EndTime.Sub(stats.StartTime)
+	if stats.TotalBlocks == 0 || duration <= 0 {
+		util.Logf("\nStats: no blocks fetched\n")
+		return
+	}
 
-	fmt.Printf("\nStats: %d blocks | %v | %.1f%% success | %.1f blocks/sec\n",
+	util.Logf("\nStats: %d blocks | %v | %.1f%% success | %.1f blocks/sec | %d throttled | rate %.1f/s\n",
 		stats.TotalBlocks,
 		duration.Round(time.Second),
 		float64(stats.SuccessfulBlocks)/float64(stats.TotalBlocks)*100,
-		float64(stats.TotalBlocks)/duration.Seconds())
+		float64(stats.TotalBlocks)/duration.Seconds(),
+		stats.ThrottledBatches,
+		stats.CurrentRate)
 }
@@ -0,0 +1,470 @@
+package solana
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+	"github.com/klauspost/compress/zstd"
+)
+
+// 本地区块归档格式：
+//   archive.json                 记录分片大小
+//   blocks_<分片起始slot>.zst     每个区块为一个独立的 zstd 帧（压缩后的 JSON），依次追加
+//   blocks_<分片起始slot>.idx     每行 "slot<TAB>偏移<TAB>长度"，偏移为 -1 表示 slot 被跳过
+// 同一 slot 重复写入时以索引中最后一条为准
+// 写入中断时索引最后一行可能不完整，或数据帧已写入而索引未写入；重新打开分片时截掉这些内容
+
+// DefaultArchiveShardSize 默认每个分片包含的 slot 数
+const DefaultArchiveShardSize = 10000
+
+// maxOpenArchiveShards 写入时最多同时打开的分片数
+const maxOpenArchiveShards = 16
+
+const archiveManifestName = "archive.json"
+
+// archiveManifest 归档元信息
+type archiveManifest struct {
+	ShardSize uint64 `json:"shard_size"`
+}
+
+// archiveIndexEntry 区块在分片文件中的位置
+type archiveIndexEntry struct {
+	offset int64 // -1 表示 slot 被跳过
+	length int64
+}
+
+// archiveShardPaths 分片数据文件与索引文件路径
+func archiveShardPaths(dir string, shardStart uint64) (string, string) {
+	base := filepath.Join(dir, fmt.Sprintf("blocks_%012d", shardStart))
+	return base + ".zst", base + ".idx"
+}
+
+// loadArchiveManifest 读取归档元信息，不存在时返回 os.ErrNotExist
+func loadArchiveManifest(dir string) (*archiveManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, archiveManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var manifest archiveManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("解析归档元信息失败: %v", err)
+	}
+	if manifest.ShardSize == 0 {
+		return nil, fmt.Errorf("归档元信息无效: shard_size 为 0")
+	}
+	return &manifest, nil
+}
+
+// archiveShard 正在写入的分片
+type archiveShard struct {
+	data     *os.File
+	index    *os.File
+	size     int64
+	lastUsed uint64
+}
+
+// BlockArchive 本地区块归档写入器，可被多个 goroutine 并发使用
+type BlockArchive struct {
+	dir       string
+	shardSize uint64
+	encoder   *zstd.Encoder
+
+	mu     sync.Mutex
+	shards map[uint64]*archiveShard
+	clock  uint64
+}
+
+// OpenBlockArchive 打开（或创建）归档目录；目录已有归档时沿用其分片大小
+func OpenBlockArchive(dir string, shardSize uint64) (*BlockArchive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建归档目录失败: %v", err)
+	}
+
+	manifest, err := loadArchiveManifest(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		if shardSize == 0 {
+			shardSize = DefaultArchiveShardSize
+		}
+		manifest = &archiveManifest{ShardSize: shardSize}
+		data, _ := json.Marshal(manifest)
+		if err := os.WriteFile(filepath.Join(dir, archiveManifestName), data, 0644); err != nil {
+			return nil, fmt.Errorf("写入归档元信息失败: %v", err)
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建 zstd 编码器失败: %v", err)
+	}
+
+	return &BlockArchive{
+		dir:       dir,
+		shardSize: manifest.ShardSize,
+		encoder:   encoder,
+		shards:    make(map[uint64]*archiveShard),
+	}, nil
+}
+
+// Write 归档一个区块
+func (a *BlockArchive) Write(slot uint64, block *model.Block) error {
+	data, err := json.Marshal(block)
+	if err != nil {
+		return fmt.Errorf("序列化区块 %d 失败: %v", slot, err)
+	}
+	// 压缩在锁外进行，多个 worker 可以并行压缩
+	compressed := a.encoder.EncodeAll(data, nil)
+	return a.append(slot, compressed)
+}
+
+// WriteSkipped 记录被跳过的 slot，回放时直接返回跳过错误
+func (a *BlockArchive) WriteSkipped(slot uint64) error {
+	return a.append(slot, nil)
+}
+
+// append 追加数据帧与索引，frame 为空表示 slot 被跳过
+func (a *BlockArchive) append(slot uint64, frame []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	shard, err := a.shardFor(slot)
+	if err != nil {
+		return err
+	}
+
+	offset := int64(-1)
+	if len(frame) > 0 {
+		if _, err := shard.data.Write(frame); err != nil {
+			return fmt.Errorf("写入归档分片失败: %v", err)
+		}
+		offset = shard.size
+		shard.size += int64(len(frame))
+	}
+	if _, err := fmt.Fprintf(shard.index, "%d\t%d\t%d\n", slot, offset, len(frame)); err != nil {
+		return fmt.Errorf("写入归档索引失败: %v", err)
+	}
+	return nil
+}
+
+// shardFor 获取 slot 所在的分片，必要时打开并关闭最久未使用的分片
+func (a *BlockArchive) shardFor(slot uint64) (*archiveShard, error) {
+	a.clock++
+	shardStart := slot - slot%a.shardSize
+	if shard, ok := a.shards[shardStart]; ok {
+		shard.lastUsed = a.clock
+		return shard, nil
+	}
+
+	if len(a.shards) >= maxOpenArchiveShards {
+		var oldestStart uint64
+		var oldest *archiveShard
+		for start, shard := range a.shards {
+			if oldest == nil || shard.lastUsed < oldest.lastUsed {
+				oldestStart, oldest = start, shard
+			}
+		}
+		oldest.close()
+		delete(a.shards, oldestStart)
+	}
+
+	dataPath, indexPath := archiveShardPaths(a.dir, shardStart)
+	if err := repairArchiveShard(dataPath, indexPath); err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(dataPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开归档分片失败: %v", err)
+	}
+	info, err := data.Stat()
+	if err != nil {
+		data.Close()
+		return nil, fmt.Errorf("读取归档分片信息失败: %v", err)
+	}
+	index, err := os.OpenFile(indexPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		data.Close()
+		return nil, fmt.Errorf("打开归档索引失败: %v", err)
+	}
+
+	shard := &archiveShard{data: data, index: index, size: info.Size(), lastUsed: a.clock}
+	a.shards[shardStart] = shard
+	return shard, nil
+}
+
+// repairArchiveShard 截掉索引末尾不完整的行，以及数据文件中没有索引记录的尾部帧，避免之后追加的记录与残留内容相连
+func repairArchiveShard(dataPath, indexPath string) error {
+	content, err := os.ReadFile(indexPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("读取归档索引失败: %v", err)
+	}
+	if complete := bytes.LastIndexByte(content, '\n') + 1; complete < len(content) {
+		if err := os.Truncate(indexPath, int64(complete)); err != nil {
+			return fmt.Errorf("修复归档索引失败: %v", err)
+		}
+		util.Logf("🔧 归档索引 %s 末尾有不完整的记录，已截断\n", indexPath)
+		content = content[:complete]
+	}
+
+	index := make(map[uint64]archiveIndexEntry)
+	readArchiveIndex(bytes.NewReader(content), index)
+	var end int64
+	for _, entry := range index {
+		if entry.offset >= 0 && entry.offset+entry.length > end {
+			end = entry.offset + entry.length
+		}
+	}
+
+	info, err := os.Stat(dataPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取归档分片信息失败: %v", err)
+	}
+	if info.Size() > end {
+		if err := os.Truncate(dataPath, end); err != nil {
+			return fmt.Errorf("修复归档分片失败: %v", err)
+		}
+		util.Logf("🔧 归档分片 %s 末尾有 %d 字节没有索引记录，已截断\n", dataPath, info.Size()-end)
+	}
+	return nil
+}
+
+func (s *archiveShard) close() error {
+	dataErr := s.data.Close()
+	indexErr := s.index.Close()
+	if dataErr != nil {
+		return dataErr
+	}
+	return indexErr
+}
+
+// Close 关闭所有打开的分片
+func (a *BlockArchive) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var firstErr error
+	for start, shard := range a.shards {
+		if err := shard.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(a.shards, start)
+	}
+	a.encoder.Close()
+	return firstErr
+}
+
+// ArchivingBlockSource 包装其他区块来源，将获取到的区块写入归档后原样输出
+type ArchivingBlockSource struct {
+	source  BlockSource
+	archive *BlockArchive
+}
+
+// NewArchivingBlockSource 创建边获取边归档的区块来源
+func NewArchivingBlockSource(source BlockSource, archive *BlockArchive) *ArchivingBlockSource {
+	return &ArchivingBlockSource{source: source, archive: archive}
+}
+
+// FetchBlocks 获取区块并归档；被跳过的 slot 也会记录，归档失败只打印日志不影响输出
+func (s *ArchivingBlockSource) FetchBlocks(ctx context.Context, slots []uint64) <-chan model.BlockResult {
+	out := make(chan model.BlockResult)
+	go func() {
+		defer close(out)
+		for result := range s.source.FetchBlocks(ctx, slots) {
+			var err error
+			switch {
+			case result.Error == nil && result.Block != nil:
+				err = s.archive.Write(result.Slot, result.Block)
+			case IsSlotSkipped(result.Error):
+				err = s.archive.WriteSkipped(result.Slot)
+			}
+			if err != nil {
+				util.Logf("⚠️ 归档区块 %d 失败: %v\n", result.Slot, err)
+			}
+
+			select {
+			case out <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FileBlockSource 从本地归档回放区块，不发起任何 RPC 请求
+type FileBlockSource struct {
+	dir       string
+	shardSize uint64
+	decoder   *zstd.Decoder
+
+	mu      sync.Mutex
+	indexes map[uint64]map[uint64]archiveIndexEntry
+}
+
+// NewFileBlockSource 打开归档目录用于回放
+func NewFileBlockSource(dir string) (*FileBlockSource, error) {
+	manifest, err := loadArchiveManifest(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档 %s 失败: %v", dir, err)
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建 zstd 解码器失败: %v", err)
+	}
+	return &FileBlockSource{
+		dir:       dir,
+		shardSize: manifest.ShardSize,
+		decoder:   decoder,
+		indexes:   make(map[uint64]map[uint64]archiveIndexEntry),
+	}, nil
+}
+
+// FetchBlocks 按 slots 顺序从归档读取区块；归档中没有的 slot 返回 missing 错误
+func (s *FileBlockSource) FetchBlocks(ctx context.Context, slots []uint64) <-chan model.BlockResult {
+	out := make(chan model.BlockResult)
+	go func() {
+		defer close(out)
+
+		files := make(map[uint64]*os.File)
+		defer func() {
+			for _, file := range files {
+				file.Close()
+			}
+		}()
+
+		for _, slot := range slots {
+			block, err := s.readBlock(slot, files)
+			result := model.BlockResult{Slot: slot, Block: block, Error: err}
+			select {
+			case out <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Close 释放解码器
+func (s *FileBlockSource) Close() {
+	s.decoder.Close()
+}
+
+// readBlock 读取单个区块，files 缓存本次回放中打开的分片文件
+func (s *FileBlockSource) readBlock(slot uint64, files map[uint64]*os.File) (*model.Block, error) {
+	shardStart := slot - slot%s.shardSize
+	index, err := s.shardIndex(shardStart)
+	if err != nil {
+		return nil, &SlotError{Slot: slot, Kind: SlotErrorDecode, Err: err}
+	}
+
+	entry, ok := index[slot]
+	if !ok {
+		return nil, &SlotError{Slot: slot, Kind: SlotErrorMissing, Err: fmt.Errorf("slot %d not in archive %s", slot, s.dir)}
+	}
+	if entry.offset < 0 {
+		rpcErr := &model.RPCError{Code: model.RPCErrorSlotSkipped, Message: fmt.Sprintf("Slot %d was skipped (archived)", slot)}
+		return nil, &SlotError{Slot: slot, Kind: SlotErrorRPC, Code: rpcErr.Code, Err: rpcErr}
+	}
+
+	file, ok := files[shardStart]
+	if !ok {
+		dataPath, _ := archiveShardPaths(s.dir, shardStart)
+		file, err = os.Open(dataPath)
+		if err != nil {
+			return nil, &SlotError{Slot: slot, Kind: SlotErrorTransport, Err: err}
+		}
+		files[shardStart] = file
+	}
+
+	frame := make([]byte, entry.length)
+	if _, err := file.ReadAt(frame, entry.offset); err != nil {
+		return nil, &SlotError{Slot: slot, Kind: SlotErrorTransport, Err: fmt.Errorf("读取归档分片失败: %v", err)}
+	}
+	data, err := s.decoder.DecodeAll(frame, nil)
+	if err != nil {
+		return nil, &SlotError{Slot: slot, Kind: SlotErrorDecode, Err: fmt.Errorf("解压区块失败: %v", err)}
+	}
+	var block model.Block
+	if err := json.Unmarshal(data, &block); err != nil {
+		return nil, &SlotError{Slot: slot, Kind: SlotErrorDecode, Err: fmt.Errorf("解析区块失败: %v", err)}
+	}
+	return &block, nil
+}
+
+// shardIndex 加载分片索引（带缓存），分片不存在时返回空索引
+func (s *FileBlockSource) shardIndex(shardStart uint64) (map[uint64]archiveIndexEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if index, ok := s.indexes[shardStart]; ok {
+		return index, nil
+	}
+
+	index := make(map[uint64]archiveIndexEntry)
+	_, indexPath := archiveShardPaths(s.dir, shardStart)
+	file, err := os.Open(indexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		s.indexes[shardStart] = index
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开归档索引失败: %v", err)
+	}
+	defer file.Close()
+
+	skipped, err := readArchiveIndex(file, index)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", indexPath, err)
+	}
+	if skipped > 0 {
+		util.Logf("⚠️ 归档索引 %s 有 %d 行格式无效，已忽略\n", indexPath, skipped)
+	}
+	s.indexes[shardStart] = index
+	return index, nil
+}
+
+// readArchiveIndex 解析索引文件，返回格式无效而被忽略的行数
+// 最后一行不完整（写入中断）时忽略；格式无效的行（如之前中断的写入与后续记录相连）跳过，其中的 slot 视为未归档
+func readArchiveIndex(r io.Reader, index map[uint64]archiveIndexEntry) (int, error) {
+	reader := bufio.NewReader(r)
+	skipped := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return skipped, nil
+		}
+		if err != nil {
+			return skipped, err
+		}
+
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) != 3 {
+			skipped++
+			continue
+		}
+		slot, slotErr := strconv.ParseUint(fields[0], 10, 64)
+		offset, offsetErr := strconv.ParseInt(fields[1], 10, 64)
+		length, lengthErr := strconv.ParseInt(fields[2], 10, 64)
+		if slotErr != nil || offsetErr != nil || lengthErr != nil {
+			skipped++
+			continue
+		}
+		index[slot] = archiveIndexEntry{offset: offset, length: length}
+	}
+}
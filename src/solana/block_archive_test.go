@@ -0,0 +1,157 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+// staticBlockSource 测试用区块来源，skipped 中的 slot 返回跳过错误
+type staticBlockSource struct {
+	blocks  map[uint64]*model.Block
+	skipped map[uint64]bool
+}
+
+func (s *staticBlockSource) FetchBlocks(ctx context.Context, slots []uint64) <-chan model.BlockResult {
+	out := make(chan model.BlockResult, len(slots))
+	for _, slot := range slots {
+		switch {
+		case s.blocks[slot] != nil:
+			out <- model.BlockResult{Slot: slot, Block: s.blocks[slot]}
+		case s.skipped[slot]:
+			out <- model.BlockResult{Slot: slot, Error: &model.RPCError{Code: model.RPCErrorSlotSkipped, Message: "skipped"}}
+		default:
+			out <- model.BlockResult{Slot: slot, Error: fmt.Errorf("unavailable")}
+		}
+	}
+	close(out)
+	return out
+}
+
+func TestBlockArchiveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	upstream := &staticBlockSource{
+		blocks: map[uint64]*model.Block{
+			98:  testBlock(98, 97),
+			99:  testBlock(99, 98),
+			101: testBlock(101, 99),
+		},
+		skipped: map[uint64]bool{100: true},
+	}
+
+	// 分片大小 100，slot 98-101 跨两个分片
+	archive, err := OpenBlockArchive(dir, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks, _ := CollectBlocks(context.Background(), NewArchivingBlockSource(upstream, archive), []uint64{98, 99, 100, 101, 102})
+	if len(blocks) != 3 {
+		t.Fatalf("archiving source returned %d blocks, want 3", len(blocks))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重新打开时沿用已有的分片大小，追加写入覆盖旧记录
+	archive, err = OpenBlockArchive(dir, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := testBlock(99, 98)
+	updated.Blockhash = "hash-99-updated"
+	if err := archive.Write(99, updated); err != nil {
+		t.Fatal(err)
+	}
+	archive.Close()
+	if _, err := os.Stat(filepath.Join(dir, "blocks_000000000100.zst")); err != nil {
+		t.Fatalf("expected second shard file: %v", err)
+	}
+
+	source, err := NewFileBlockSource(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	replayed, slotErrors := CollectBlocks(context.Background(), source, []uint64{98, 99, 100, 101, 102})
+	if len(replayed) != 3 || replayed[98].Blockhash != "hash-98" || replayed[101].PreviousBlockhash != "hash-99" {
+		t.Fatalf("replayed = %v, want slots 98, 99 and 101", replayed)
+	}
+	if replayed[99].Blockhash != "hash-99-updated" {
+		t.Errorf("slot 99 blockhash = %s, want latest archived copy", replayed[99].Blockhash)
+	}
+	if !IsSlotSkipped(slotErrors[100]) {
+		t.Errorf("slot 100 error = %v, want skipped", slotErrors[100])
+	}
+	if slotErr, ok := slotErrors[102].(*SlotError); !ok || slotErr.Kind != SlotErrorMissing {
+		t.Errorf("slot 102 error = %v, want missing", slotErrors[102])
+	}
+}
+
+func TestBlockArchiveRecoversFromInterruptedWrite(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := OpenBlockArchive(dir, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Write(10, testBlock(10, 9)); err != nil {
+		t.Fatal(err)
+	}
+	archive.Close()
+
+	// 模拟中断：数据帧已写入但索引只写了半行
+	dataPath, indexPath := archiveShardPaths(dir, 0)
+	appendFile := func(path, content string) {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if _, err := file.WriteString(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appendFile(dataPath, "partial frame")
+	appendFile(indexPath, "11\t4")
+
+	archive, err = OpenBlockArchive(dir, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Write(12, testBlock(12, 10)); err != nil {
+		t.Fatal(err)
+	}
+	archive.Close()
+
+	source, err := NewFileBlockSource(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	blocks, slotErrors := CollectBlocks(context.Background(), source, []uint64{10, 11, 12})
+	if blocks[10] == nil || blocks[10].Blockhash != "hash-10" || blocks[12] == nil || blocks[12].Blockhash != "hash-12" {
+		t.Fatalf("blocks = %v, errors = %v, want slots 10 and 12 readable", blocks, slotErrors)
+	}
+	if _, ok := slotErrors[11]; !ok {
+		t.Error("slot 11 with an incomplete index record should not be archived")
+	}
+}
+
+func TestReadArchiveIndexSkipsMalformedLines(t *testing.T) {
+	// 第二行为中断的写入与下一条记录相连
+	content := "10\t0\t20\n11\t2011\t0\t20\n12\t-1\t0\n13\t40"
+	index := make(map[uint64]archiveIndexEntry)
+	skipped, err := readArchiveIndex(strings.NewReader(content), index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 1 || len(index) != 2 || index[12].offset != -1 {
+		t.Errorf("skipped = %d, index = %v, want slots 10 and 12 with one skipped line", skipped, index)
+	}
+}
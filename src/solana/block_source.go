@@ -0,0 +1,60 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+// BlockSource 区块数据来源，调用方无需关心具体的获取方式
+type BlockSource interface {
+	// FetchBlocks 获取 slots 对应的区块，每个 slot 输出一条结果（失败时 Error 非空），结果顺序不保证
+	// 全部输出后关闭 channel；ctx 取消后可能提前关闭，未输出的 slot 视为失败
+	FetchBlocks(ctx context.Context, slots []uint64) <-chan model.BlockResult
+}
+
+// NewBlockSource 创建默认区块来源：经节点池的批量 JSON-RPC，batchSize 为单次批量请求的区块数
+// filter 不为空时，区块中只保留通过过滤的交易
+func NewBlockSource(apiKey string, batchSize int, filter TransactionFilter) BlockSource {
+	batchConfig := DefaultBatchRPCConfig()
+	batchConfig.APIKey = apiKey
+	batchConfig.MaxBatchSize = batchSize
+	batchConfig.TransactionFilter = filter
+	return NewBatchRPCFetcher(batchConfig)
+}
+
+// CollectBlocks 读取 BlockSource 的全部结果，返回成功的区块与失败 slot 的错误
+func CollectBlocks(ctx context.Context, source BlockSource, slots []uint64) (map[uint64]*model.Block, map[uint64]error) {
+	blocks := make(map[uint64]*model.Block)
+	slotErrors := make(map[uint64]error)
+
+	for result := range source.FetchBlocks(ctx, slots) {
+		if result.Error == nil && result.Block != nil {
+			blocks[result.Slot] = result.Block
+			continue
+		}
+		err := result.Error
+		if err == nil {
+			err = fmt.Errorf("block not found for slot %d", result.Slot)
+		}
+		slotErrors[result.Slot] = err
+	}
+
+	// 提前关闭时没有结果的 slot
+	for _, slot := range slots {
+		if _, ok := blocks[slot]; ok {
+			continue
+		}
+		if _, ok := slotErrors[slot]; ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			slotErrors[slot] = err
+		} else {
+			slotErrors[slot] = fmt.Errorf("no result for slot %d", slot)
+		}
+	}
+
+	return blocks, slotErrors
+}
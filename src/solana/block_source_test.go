@@ -0,0 +1,71 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+func TestBatchRPCFetcherRetriesTransientSlots(t *testing.T) {
+	// slot 11 被跳过；slot 12 第一次请求时尚不可用
+	var mu sync.Mutex
+	requested := make(map[uint64]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []model.RPCRequest
+		json.NewDecoder(r.Body).Decode(&requests)
+		responses := make([]model.GetBlockResponse, len(requests))
+
+		mu.Lock()
+		for i, request := range requests {
+			slot := uint64(request.Params[0].(float64))
+			requested[slot]++
+			responses[i] = model.GetBlockResponse{JSONRPC: "2.0", ID: request.ID}
+			switch {
+			case slot == 11:
+				responses[i].Error = &model.RPCError{Code: model.RPCErrorSlotSkipped, Message: "skipped"}
+			case slot == 12 && requested[slot] == 1:
+				responses[i].Error = &model.RPCError{Code: -32004, Message: "Block not available"}
+			default:
+				responses[i].Result = &model.Block{Blockhash: fmt.Sprintf("hash-%d", slot)}
+			}
+		}
+		mu.Unlock()
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	pool, err := NewEndpointPool([]config.EndpointConfig{{URL: server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetcher := NewBatchRPCFetcher(&BatchRPCConfig{MaxBatchSize: 2, MaxConcurrentBatches: 2, RetryAttempts: 2})
+	fetcher.pool = pool
+
+	var source BlockSource = fetcher
+	blocks, slotErrors := CollectBlocks(context.Background(), source, []uint64{10, 11, 12, 13})
+
+	if len(blocks) != 3 || blocks[10] == nil || blocks[12] == nil || blocks[13] == nil {
+		t.Fatalf("blocks = %v, want slots 10, 12 and 13", blocks)
+	}
+	if slotErr, ok := slotErrors[11].(*SlotError); !ok || slotErr.Kind != SlotErrorRPC || !IsSlotSkipped(slotErr) {
+		t.Errorf("slot 11 error = %v, want skipped", slotErrors[11])
+	}
+	if len(slotErrors) != 1 {
+		t.Errorf("slotErrors = %v, want only slot 11", slotErrors)
+	}
+	if requested[11] != 1 || requested[12] != 2 {
+		t.Errorf("requests = %v, want skipped slot fetched once and unavailable slot retried once", requested)
+	}
+
+	stats := fetcher.GetStats()
+	if stats.TotalBlocks != 4 || stats.SuccessfulBlocks != 3 || stats.FailedBlocks != 1 || stats.TotalRetries != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
@@ -0,0 +1,186 @@
+package solana
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+// decodeBatchResponse 流式解析批量 getBlock 响应，每解析完一个响应调用一次 handle
+// 交易逐笔解码并经 filter 过滤，内存占用取决于保留的交易而非整个响应体
+// 节点返回单个响应对象（通常是整批请求的错误）而非数组时，不调用 handle，直接返回该对象
+func decodeBatchResponse(r io.Reader, filter TransactionFilter, handle func(model.GetBlockResponse)) (*model.GetBlockResponse, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		response, err := decodeResponseObject(dec, filter)
+		if err != nil {
+			return nil, err
+		}
+		return &response, nil
+	case json.Delim('['):
+	default:
+		return nil, fmt.Errorf("unexpected token %v at start of batch response", tok)
+	}
+
+	for dec.More() {
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, err
+		}
+		response, err := decodeResponseObject(dec, filter)
+		if err != nil {
+			return nil, err
+		}
+		handle(response)
+	}
+	return nil, expectDelim(dec, ']')
+}
+
+// decodeResponseObject 解析单个 JSON-RPC 响应，起始的 { 已被读取
+func decodeResponseObject(dec *json.Decoder, filter TransactionFilter) (model.GetBlockResponse, error) {
+	var response model.GetBlockResponse
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return response, err
+		}
+		switch key {
+		case "jsonrpc":
+			err = dec.Decode(&response.JSONRPC)
+		case "id":
+			err = dec.Decode(&response.ID)
+		case "error":
+			err = dec.Decode(&response.Error)
+		case "result":
+			response.Result, err = decodeBlock(dec, filter)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return response, err
+		}
+	}
+	return response, expectDelim(dec, '}')
+}
+
+// decodeBlock 解析区块对象，transactions 数组逐笔解码，其余字段较小，整体解码
+func decodeBlock(dec *json.Decoder, filter TransactionFilter) (*model.Block, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("unexpected token %v for block", tok)
+	}
+
+	fields := make(map[string]json.RawMessage)
+	var transactions []model.TransactionInfo
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return nil, err
+		}
+		if key != "transactions" {
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			fields[key] = value
+			continue
+		}
+
+		if transactions, err = decodeTransactions(dec, filter); err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+
+	var block model.Block
+	header, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(header, &block); err != nil {
+		return nil, err
+	}
+	block.Transactions = transactions
+	return &block, nil
+}
+
+// decodeTransactions 逐笔解码 transactions 数组，未通过 filter 的交易解码后立即丢弃
+func decodeTransactions(dec *json.Decoder, filter TransactionFilter) ([]model.TransactionInfo, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("unexpected token %v for transactions", tok)
+	}
+
+	transactions := []model.TransactionInfo{}
+	for dec.More() {
+		var tx model.TransactionInfo
+		if err := dec.Decode(&tx); err != nil {
+			return nil, err
+		}
+		if filter == nil || filter(&tx) {
+			transactions = append(transactions, tx)
+		}
+	}
+	return transactions, expectDelim(dec, ']')
+}
+
+// objectKey 读取对象的下一个键
+func objectKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected token %v, want object key", tok)
+	}
+	return key, nil
+}
+
+// expectDelim 读取下一个 token 并确认为指定分隔符
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("unexpected token %v, want %v", tok, delim)
+	}
+	return nil
+}
+
+// readErrorRecorder 记录读取响应体时的错误，用于区分网络错误与格式错误
+type readErrorRecorder struct {
+	r   io.Reader
+	err error
+}
+
+func (r *readErrorRecorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,60 @@
+package solana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+const streamTestBody = `[
+	{"jsonrpc":"2.0","id":2,"result":null,"error":{"code":-32007,"message":"Slot 11 was skipped"}},
+	{"jsonrpc":"2.0","id":1,"result":{
+		"blockhash":"hash-10","previousBlockhash":"hash-9","parentSlot":9,"blockTime":1700000000,"extra":{"nested":[1,2]},
+		"transactions":[
+			{"transaction":{"signatures":["vote"],"message":{"accountKeys":["Vote111111111111111111111111111111111111111"]}}},
+			{"transaction":{"signatures":["swap"],"message":{"accountKeys":["wallet","` + config.TOKEN_PROGRAM_ID + `"]}}}
+		]}}
+]`
+
+func TestDecodeBatchResponseFiltersTransactions(t *testing.T) {
+	responses := make(map[int]model.GetBlockResponse)
+	single, err := decodeBatchResponse(strings.NewReader(streamTestBody), DefaultTransactionFilter(), func(response model.GetBlockResponse) {
+		responses[response.ID] = response
+	})
+	if err != nil || single != nil {
+		t.Fatalf("decodeBatchResponse() = %v, %v", single, err)
+	}
+
+	block := responses[1].Result
+	if block == nil || block.Blockhash != "hash-10" || block.ParentSlot != 9 || block.BlockTime == nil || *block.BlockTime != 1700000000 {
+		t.Fatalf("block = %+v, want header fields decoded", block)
+	}
+	if len(block.Transactions) != 1 || block.Transactions[0].Transaction.Signatures[0] != "swap" {
+		t.Errorf("transactions = %+v, want only the token program transaction", block.Transactions)
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != model.RPCErrorSlotSkipped || responses[2].Result != nil {
+		t.Errorf("response 2 = %+v, want skipped error", responses[2])
+	}
+}
+
+func TestProcessBatchKeepsResultsBeforeTruncation(t *testing.T) {
+	// 第一个响应完整，第二个响应中途截断
+	body := `[{"jsonrpc":"2.0","id":1,"result":{"blockhash":"hash-10","transactions":[]}},{"jsonrpc":"2.0","id":2,"result":{"blockhash":`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	results, slotErrors := processBatch(context.Background(), []uint64{10, 11}, server.URL, http.DefaultClient, "", nil)
+	if results[10] == nil || results[10].Blockhash != "hash-10" {
+		t.Fatalf("results = %v, want slot 10 decoded before truncation", results)
+	}
+	if slotErr, ok := slotErrors[11].(*SlotError); !ok || slotErr.Kind != SlotErrorDecode {
+		t.Errorf("slot 11 error = %v, want decode", slotErrors[11])
+	}
+}
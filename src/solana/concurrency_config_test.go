@@ -0,0 +1,32 @@
+package solana
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConcurrencyConfigBatchRPCConfig(t *testing.T) {
+	cases := []struct {
+		name      string
+		config    *ConcurrencyConfig
+		wantBatch int
+	}{
+		{"default", DefaultConcurrencyConfig(), MaxRPCBatchSize},
+		{"high performance", HighPerformanceConfig(), MaxRPCBatchSize},
+		{"conservative", ConservativeConfig(), 50},
+		{"small batch", &ConcurrencyConfig{BatchSize: 10, MaxConcurrentWorkers: 40, RequestTimeout: time.Second, BatchTimeout: time.Minute}, 10},
+	}
+	for _, c := range cases {
+		batchConfig := c.config.BatchRPCConfig()
+		if batchConfig.MaxBatchSize != c.wantBatch {
+			t.Errorf("%s: MaxBatchSize = %d, want %d", c.name, batchConfig.MaxBatchSize, c.wantBatch)
+		}
+		if batchConfig.RequestTimeout != c.config.RequestTimeout || batchConfig.BatchTimeout != c.config.BatchTimeout {
+			t.Errorf("%s: timeouts = %v / %v, want %v / %v", c.name, batchConfig.RequestTimeout, batchConfig.BatchTimeout, c.config.RequestTimeout, c.config.BatchTimeout)
+		}
+	}
+
+	if batchConfig := (&ConcurrencyConfig{BatchSize: 10, MaxConcurrentWorkers: 40}).BatchRPCConfig(); batchConfig.MaxConcurrentBatches != 4 {
+		t.Errorf("MaxConcurrentBatches = %d, want 4", batchConfig.MaxConcurrentBatches)
+	}
+}
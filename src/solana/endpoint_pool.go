@@ -0,0 +1,277 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/util"
+	"golang.org/x/time/rate"
+)
+
+// 节点健康评估参数
+const (
+	endpointEWMAAlpha          = 0.2              // 错误率与延迟的指数滑动平均系数
+	endpointEjectErrorRate     = 0.5              // 错误率超过该值时剔除
+	endpointEjectMinSamples    = 5                // 按错误率剔除前至少需要的请求数
+	endpointEjectConsecutive   = 3                // 连续失败次数达到该值时剔除
+	endpointEjectDuration      = 30 * time.Second // 剔除时长
+	endpointDefaultLatency     = 500 * time.Millisecond
+	endpointMinLatencyForScore = 50 * time.Millisecond
+)
+
+// HeliusRPCURL Helius 主网 RPC 地址
+func HeliusRPCURL(apiKey string) string {
+	return fmt.Sprintf("https://mainnet.helius-rpc.com/?api-key=%s", apiKey)
+}
+
+// Endpoint RPC 节点及其健康状态
+type Endpoint struct {
+	URL     string
+	Weight  int
+	limiter *rate.Limiter
+
+	mu                  sync.Mutex
+	requests            uint64
+	failures            uint64
+	consecutiveFailures int
+	errorRate           float64       // 错误率 EWMA
+	latency             time.Duration // 延迟 EWMA
+	ejectedUntil        time.Time
+}
+
+// EndpointStats 节点统计
+type EndpointStats struct {
+	URL          string
+	Requests     uint64
+	Failures     uint64
+	ErrorRate    float64
+	Latency      time.Duration
+	Healthy      bool
+	EjectedUntil time.Time
+}
+
+// Wait 按节点限流等待
+func (e *Endpoint) Wait(ctx context.Context) error {
+	if e.limiter == nil {
+		return nil
+	}
+	return e.limiter.Wait(ctx)
+}
+
+// healthy 节点是否可用（未被剔除）
+func (e *Endpoint) healthy(now time.Time) bool {
+	return !now.Before(e.ejectedUntil)
+}
+
+// score 节点选择权重：流量权重 × 成功率 ÷ 延迟
+func (e *Endpoint) score() float64 {
+	latency := e.latency
+	if latency < endpointMinLatencyForScore {
+		latency = endpointMinLatencyForScore
+	}
+	return float64(e.Weight) * (1 - e.errorRate) / latency.Seconds()
+}
+
+// EndpointPool 按健康评分在多个 RPC 节点间分配请求，失败较多的节点暂时剔除
+type EndpointPool struct {
+	endpoints []*Endpoint
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewEndpointPool 根据配置创建节点池
+func NewEndpointPool(configs []config.EndpointConfig) (*EndpointPool, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("endpoint pool requires at least one endpoint")
+	}
+
+	pool := &EndpointPool{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	for _, cfg := range configs {
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("endpoint url is empty")
+		}
+		endpoint := &Endpoint{
+			URL:     cfg.URL,
+			Weight:  cfg.Weight,
+			latency: endpointDefaultLatency,
+		}
+		if endpoint.Weight <= 0 {
+			endpoint.Weight = 1
+		}
+		if cfg.RateLimit > 0 {
+			burst := cfg.Burst
+			if burst <= 0 {
+				burst = int(cfg.RateLimit)
+			}
+			if burst < 1 {
+				burst = 1
+			}
+			endpoint.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), burst)
+		}
+		pool.endpoints = append(pool.endpoints, endpoint)
+	}
+	return pool, nil
+}
+
+var (
+	endpointPoolsMu sync.Mutex
+	endpointPools   = map[string]*EndpointPool{}
+)
+
+// RequireRPCEndpoint 校验 RPC 节点来源：未配置 solana.endpoints 时必须提供 Helius API key
+func RequireRPCEndpoint(apiKey string) error {
+	if apiKey == "" && len(config.SvcConfig.Solana.Endpoints) == 0 {
+		return fmt.Errorf("缺少 Solana API key 或 solana.endpoints 配置")
+	}
+	return nil
+}
+
+// endpointPoolFor 获取共享节点池：配置了 solana.endpoints 时使用配置的节点，否则使用 apiKey 对应的 Helius 节点
+func endpointPoolFor(apiKey string) *EndpointPool {
+	endpointPoolsMu.Lock()
+	defer endpointPoolsMu.Unlock()
+
+	configs := config.SvcConfig.Solana.Endpoints
+	key := "config"
+	if len(configs) == 0 {
+		configs = []config.EndpointConfig{{URL: HeliusRPCURL(apiKey)}}
+		key = "helius:" + apiKey
+	}
+
+	if pool, ok := endpointPools[key]; ok {
+		return pool
+	}
+	pool, err := NewEndpointPool(configs)
+	if err != nil {
+		// 配置无效时回退到 Helius 节点
+		util.Logf("⚠️ 节点池配置无效: %v，使用 Helius 节点\n", err)
+		pool, _ = NewEndpointPool([]config.EndpointConfig{{URL: HeliusRPCURL(apiKey)}})
+	}
+	endpointPools[key] = pool
+	return pool
+}
+
+// Pick 按健康评分加权随机选择节点，exclude 中的节点仅在没有其他可用节点时使用
+// 所有节点都被剔除时选择最早恢复的节点，保证请求不会因节点池为空而中断
+func (p *EndpointPool) Pick(exclude map[*Endpoint]bool) *Endpoint {
+	now := time.Now()
+
+	var candidates []*Endpoint
+	var scores []float64
+	total := 0.0
+	for _, onlyUnexcluded := range []bool{true, false} {
+		for _, endpoint := range p.endpoints {
+			if onlyUnexcluded && exclude[endpoint] {
+				continue
+			}
+			endpoint.mu.Lock()
+			healthy, score := endpoint.healthy(now), endpoint.score()
+			endpoint.mu.Unlock()
+			if !healthy {
+				continue
+			}
+			candidates = append(candidates, endpoint)
+			scores = append(scores, score)
+			total += score
+		}
+		if len(candidates) > 0 {
+			break
+		}
+	}
+
+	if len(candidates) == 0 {
+		var earliest *Endpoint
+		var earliestUntil time.Time
+		for _, endpoint := range p.endpoints {
+			endpoint.mu.Lock()
+			until := endpoint.ejectedUntil
+			endpoint.mu.Unlock()
+			if earliest == nil || until.Before(earliestUntil) {
+				earliest, earliestUntil = endpoint, until
+			}
+		}
+		return earliest
+	}
+
+	if total <= 0 {
+		return candidates[0]
+	}
+
+	p.mu.Lock()
+	target := p.rng.Float64() * total
+	p.mu.Unlock()
+	for i, score := range scores {
+		target -= score
+		if target < 0 {
+			return candidates[i]
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// Report 记录一次请求结果，更新错误率与延迟，达到阈值时剔除节点
+func (p *EndpointPool) Report(endpoint *Endpoint, latency time.Duration, success bool) {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+
+	endpoint.requests++
+	failure := 0.0
+	if success {
+		endpoint.consecutiveFailures = 0
+		endpoint.latency = time.Duration(endpointEWMAAlpha*float64(latency) + (1-endpointEWMAAlpha)*float64(endpoint.latency))
+	} else {
+		endpoint.failures++
+		endpoint.consecutiveFailures++
+		failure = 1
+	}
+	endpoint.errorRate = endpointEWMAAlpha*failure + (1-endpointEWMAAlpha)*endpoint.errorRate
+
+	eject := endpoint.consecutiveFailures >= endpointEjectConsecutive ||
+		(endpoint.requests >= endpointEjectMinSamples && endpoint.errorRate > endpointEjectErrorRate)
+	if eject && endpoint.healthy(time.Now()) {
+		endpoint.ejectedUntil = time.Now().Add(endpointEjectDuration)
+		endpoint.consecutiveFailures = 0
+		// 恢复后以中等错误率重新开始，避免立刻再次被剔除
+		endpoint.errorRate = endpointEjectErrorRate / 2
+		util.Logf("🚫 RPC 节点 %s 暂时剔除 %v (失败 %d/%d)\n", redactURL(endpoint.URL), endpointEjectDuration, endpoint.failures, endpoint.requests)
+	}
+}
+
+// Size 节点数
+func (p *EndpointPool) Size() int {
+	return len(p.endpoints)
+}
+
+// Stats 返回各节点统计
+func (p *EndpointPool) Stats() []EndpointStats {
+	now := time.Now()
+	stats := make([]EndpointStats, 0, len(p.endpoints))
+	for _, endpoint := range p.endpoints {
+		endpoint.mu.Lock()
+		stats = append(stats, EndpointStats{
+			URL:          redactURL(endpoint.URL),
+			Requests:     endpoint.requests,
+			Failures:     endpoint.failures,
+			ErrorRate:    endpoint.errorRate,
+			Latency:      endpoint.latency,
+			Healthy:      endpoint.healthy(now),
+			EjectedUntil: endpoint.ejectedUntil,
+		})
+		endpoint.mu.Unlock()
+	}
+	return stats
+}
+
+// redactURL 隐藏 URL 中的查询参数（通常包含 API key）
+func redactURL(url string) string {
+	if i := strings.Index(url, "?"); i >= 0 {
+		return url[:i] + "?***"
+	}
+	return url
+}
@@ -0,0 +1,123 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+func TestEndpointPoolEjectsAfterConsecutiveFailures(t *testing.T) {
+	pool, err := NewEndpointPool([]config.EndpointConfig{{URL: "http://a"}, {URL: "http://b", Weight: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := pool.endpoints[0]
+
+	for i := 0; i < endpointEjectConsecutive; i++ {
+		pool.Report(bad, 100*time.Millisecond, false)
+	}
+	if bad.healthy(time.Now()) {
+		t.Fatal("expected endpoint to be ejected after consecutive failures")
+	}
+	for i := 0; i < 20; i++ {
+		if endpoint := pool.Pick(nil); endpoint == bad {
+			t.Fatal("picked ejected endpoint")
+		}
+	}
+
+	// 全部剔除时仍返回最早恢复的节点
+	good := pool.endpoints[1]
+	for i := 0; i < endpointEjectConsecutive; i++ {
+		pool.Report(good, 100*time.Millisecond, false)
+	}
+	if endpoint := pool.Pick(nil); endpoint != bad {
+		t.Errorf("Pick() = %s, want earliest recovering endpoint %s", endpoint.URL, bad.URL)
+	}
+}
+
+func TestFetchBatchWithFailoverRetriesOnAnotherEndpoint(t *testing.T) {
+	// 两个节点都将 slot 101 报告为被跳过；失败节点的长期存储中缺少其余 slot
+	newServer := func(available bool, hits *int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*hits++
+			var requests []model.RPCRequest
+			json.NewDecoder(r.Body).Decode(&requests)
+			responses := make([]model.GetBlockResponse, len(requests))
+			for i, request := range requests {
+				slot := uint64(request.Params[0].(float64))
+				responses[i] = model.GetBlockResponse{JSONRPC: "2.0", ID: request.ID}
+				switch {
+				case slot == 101:
+					responses[i].Error = &model.RPCError{Code: model.RPCErrorSlotSkipped, Message: "skipped"}
+				case available:
+					responses[i].Result = &model.Block{Blockhash: fmt.Sprintf("hash-%d", slot)}
+				default:
+					responses[i].Error = &model.RPCError{Code: model.RPCErrorLongTermStorageSkipped, Message: "missing in long-term storage"}
+				}
+			}
+			json.NewEncoder(w).Encode(responses)
+		}))
+	}
+
+	var failingHits, healthyHits int
+	failing := newServer(false, &failingHits)
+	defer failing.Close()
+	healthy := newServer(true, &healthyHits)
+	defer healthy.Close()
+
+	// 失败节点权重远高于正常节点，第一次几乎必然选中
+	pool, err := NewEndpointPool([]config.EndpointConfig{{URL: failing.URL, Weight: 1000000}, {URL: healthy.URL, Weight: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, slotErrors := fetchBatchWithFailover(context.Background(), pool, []uint64{100, 101, 102}, http.DefaultClient, "", nil, 0)
+	if failingHits != 1 || healthyHits != 1 {
+		t.Fatalf("hits = failing %d, healthy %d, want 1 each", failingHits, healthyHits)
+	}
+	if len(results) != 2 || results[100] == nil || results[102] == nil {
+		t.Fatalf("results = %v, want slots 100 and 102", results)
+	}
+	if slotErr, ok := slotErrors[101].(*SlotError); !ok || slotErr.Kind != SlotErrorRPC || !IsSlotSkipped(slotErr) {
+		t.Errorf("slot 101 error = %v, want skipped", slotErrors[101])
+	}
+	if _, ok := slotErrors[100]; ok {
+		t.Error("slot 100 should not keep the error from the failing endpoint")
+	}
+	if stats := pool.Stats(); stats[0].Failures != 1 || stats[1].Failures != 0 {
+		t.Errorf("stats = %+v, want one failure on the first endpoint", stats)
+	}
+}
+
+func TestFetchBatchWithFailoverBlockNotAvailableKeepsHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []model.RPCRequest
+		json.NewDecoder(r.Body).Decode(&requests)
+		responses := make([]model.GetBlockResponse, len(requests))
+		for i, request := range requests {
+			responses[i] = model.GetBlockResponse{JSONRPC: "2.0", ID: request.ID,
+				Error: &model.RPCError{Code: model.RPCErrorBlockNotAvailable, Message: "Block not available"}}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	pool, err := NewEndpointPool([]config.EndpointConfig{{URL: server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, slotErrors := fetchBatchWithFailover(context.Background(), pool, []uint64{100}, http.DefaultClient, "", nil, 0)
+	if !IsBlockNotAvailable(slotErrors[100]) {
+		t.Errorf("slot 100 error = %v, want block not available", slotErrors[100])
+	}
+	if stats := pool.Stats(); stats[0].Failures != 0 {
+		t.Errorf("stats = %+v, want no failure for unavailable block", stats)
+	}
+}
@@ -0,0 +1,294 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-solana-parse/src/model"
+	"github.com/go-solana-parse/src/util"
+)
+
+// 确认级别
+const (
+	CommitmentConfirmed = "confirmed"
+	CommitmentFinalized = "finalized"
+)
+
+// maxSlotsPerPoll 单轮最多获取的 slot 数，落后较多时分多轮追赶
+const maxSlotsPerPoll = 200
+
+// maxFollowBackoff 获取失败时退避等待的上限
+const maxFollowBackoff = 30 * time.Second
+
+// getSlotResponse getSlot 响应结构
+type getSlotResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  uint64          `json:"result"`
+	Error   *model.RPCError `json:"error,omitempty"`
+}
+
+// GetSlot 获取指定确认级别下的最新 slot
+func GetSlot(apiKey, commitment string) (uint64, error) {
+	pool := endpointPoolFor(apiKey)
+	endpoint := pool.Pick(nil)
+
+	requestBody := model.RPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "getSlot",
+		Params:  []interface{}{map[string]interface{}{"commitment": commitment}},
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", endpoint.URL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := getHighPerfClient().Do(req)
+	if err != nil {
+		pool.Report(endpoint, time.Since(start), false)
+		return 0, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		pool.Report(endpoint, time.Since(start), false)
+		return 0, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	var response getSlotResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		pool.Report(endpoint, time.Since(start), false)
+		return 0, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	pool.Report(endpoint, time.Since(start), response.Error == nil)
+	if response.Error != nil {
+		return 0, response.Error
+	}
+	return response.Result, nil
+}
+
+// FollowOptions 跟随链上最新区块的参数
+type FollowOptions struct {
+	APIKey       string
+	Commitment   string        // confirmed 或 finalized
+	StartSlot    uint64        // 起始 slot，0 表示从当前最新 slot 开始
+	PollInterval time.Duration // getSlot 轮询间隔
+	BatchSize    int           // 单次批量 RPC 请求的区块数
+	ReorgBuffer  int           // 暂缓输出的 slot 数，输出前校验缓冲区内区块的父哈希
+}
+
+// DefaultFollowOptions 默认跟随参数：confirmed 级别，保留 8 个 slot 的回滚缓冲
+func DefaultFollowOptions(apiKey string) FollowOptions {
+	return FollowOptions{
+		APIKey:       apiKey,
+		Commitment:   CommitmentConfirmed,
+		PollInterval: 400 * time.Millisecond,
+		BatchSize:    10,
+		ReorgBuffer:  8,
+	}
+}
+
+// BlockHandler 按 slot 升序处理区块，返回错误时该区块在下一轮重新处理
+type BlockHandler func(slot uint64, block *model.Block) error
+
+// followedBlock 缓冲区中已获取、尚未输出的区块
+type followedBlock struct {
+	slot  uint64
+	block *model.Block
+}
+
+// chainFollower 跟随状态：next 为下一个待获取的 slot，buffer 按 slot 升序保存未输出的区块
+type chainFollower struct {
+	opts    FollowOptions
+	getTip  func() (uint64, error)
+	fetch   func(slots []uint64) (map[uint64]*model.Block, map[uint64]error)
+	handler BlockHandler
+
+	next    uint64
+	buffer  []followedBlock
+	emitted *followedBlock // 最近输出的区块
+}
+
+// Follow 轮询 getSlot 跟随链上最新区块，按 slot 顺序交给 handler，直到 ctx 取消
+// 被 leader 跳过的 slot 直接越过；尚不可用的 slot 在下一轮重试
+func Follow(ctx context.Context, opts FollowOptions, handler BlockHandler) error {
+	if opts.Commitment != CommitmentConfirmed && opts.Commitment != CommitmentFinalized {
+		return fmt.Errorf("不支持的确认级别: %s", opts.Commitment)
+	}
+	if opts.BatchSize <= 0 || opts.PollInterval <= 0 || opts.ReorgBuffer < 0 {
+		return fmt.Errorf("跟随参数无效: batch %d, poll %v, reorg buffer %d", opts.BatchSize, opts.PollInterval, opts.ReorgBuffer)
+	}
+
+	follower := &chainFollower{
+		opts: opts,
+		getTip: func() (uint64, error) {
+			return GetSlot(opts.APIKey, opts.Commitment)
+		},
+		// 尚未产出的区块在下一轮轮询时重新获取，获取器本身不重试
+		fetch: func(slots []uint64) (map[uint64]*model.Block, map[uint64]error) {
+			return CollectBlocks(ctx, sharedBlockFetcher(opts.APIKey, opts.BatchSize, opts.Commitment, 0), slots)
+		},
+		handler: handler,
+		next:    opts.StartSlot,
+	}
+
+	util.Logf("📡 开始跟随链上区块: commitment=%s, 回滚缓冲=%d\n", opts.Commitment, opts.ReorgBuffer)
+
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	backoff := opts.PollInterval
+	for {
+		result, err := follower.poll()
+		if err != nil {
+			util.Logf("⚠️ 跟随区块失败: %v\n", err)
+		}
+
+		// 落后且本轮有进展时不等待，立即进入下一轮追赶
+		if err == nil && result.advanced && !result.caughtUp {
+			backoff = opts.PollInterval
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		// 出错时按指数退避等待，区块尚未产出时按轮询间隔等待
+		if err == nil && result.fetchErr == nil {
+			backoff = opts.PollInterval
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+			continue
+		}
+
+		if result.fetchErr != nil {
+			util.Logf("⚠️ 获取 slot %d 失败，%v 后重试: %v\n", follower.next, backoff, result.fetchErr)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxFollowBackoff {
+			backoff = maxFollowBackoff
+		}
+	}
+}
+
+// pollResult 一轮跟随的结果
+type pollResult struct {
+	caughtUp bool  // next 已超过最新 slot
+	advanced bool  // 本轮 next 向前推进
+	fetchErr error // 获取区块停在的 slot 的错误，区块尚未产出（-32004）时为空
+}
+
+// poll 执行一轮：获取新区块、校验缓冲区、输出超出回滚缓冲深度的区块
+func (f *chainFollower) poll() (pollResult, error) {
+	var result pollResult
+	tip, err := f.getTip()
+	if err != nil {
+		return result, fmt.Errorf("获取最新 slot 失败: %v", err)
+	}
+	if f.next == 0 {
+		f.next = tip
+	}
+	start := f.next
+
+	if f.next <= tip {
+		end := tip
+		if end-f.next+1 > maxSlotsPerPoll {
+			end = f.next + maxSlotsPerPoll - 1
+		}
+		slots := make([]uint64, 0, end-f.next+1)
+		for slot := f.next; slot <= end; slot++ {
+			slots = append(slots, slot)
+		}
+
+		blocks, slotErrors := f.fetch(slots)
+		for _, slot := range slots {
+			if block, ok := blocks[slot]; ok && block != nil {
+				f.buffer = append(f.buffer, followedBlock{slot: slot, block: block})
+				f.next = slot + 1
+				continue
+			}
+			if IsSlotSkipped(slotErrors[slot]) {
+				f.next = slot + 1
+				continue
+			}
+			// 区块尚不可用或临时错误，保持顺序，下一轮从这里继续
+			if !IsBlockNotAvailable(slotErrors[slot]) {
+				result.fetchErr = slotErrors[slot]
+				if result.fetchErr == nil {
+					result.fetchErr = fmt.Errorf("slot %d 没有返回区块", slot)
+				}
+			}
+			break
+		}
+	}
+
+	result.advanced = f.next > start
+	f.verifyBuffer()
+
+	for len(f.buffer) > 0 && f.buffer[0].slot+uint64(f.opts.ReorgBuffer) <= tip {
+		head := f.buffer[0]
+		if err := f.handler(head.slot, head.block); err != nil {
+			return result, fmt.Errorf("处理区块 %d 失败: %v", head.slot, err)
+		}
+		f.emitted = &head
+		f.buffer = f.buffer[1:]
+	}
+
+	result.caughtUp = f.next > tip
+	return result, nil
+}
+
+// verifyBuffer 校验缓冲区内区块与前一个区块的父子关系
+// 不一致说明前一个区块所在分叉已被放弃（或被视为跳过的 slot 实际出块），丢弃前一个区块及之后的缓冲并重新获取
+func (f *chainFollower) verifyBuffer() {
+	for i := range f.buffer {
+		var prev *followedBlock
+		if i > 0 {
+			prev = &f.buffer[i-1]
+		} else {
+			prev = f.emitted
+		}
+		if prev == nil {
+			continue
+		}
+
+		block := f.buffer[i].block
+		if block.ParentSlot == prev.slot && block.PreviousBlockhash == prev.block.Blockhash {
+			continue
+		}
+
+		if i == 0 {
+			// 已输出的区块无法撤回，说明回滚深度超过缓冲
+			util.Logf("⚠️ slot %d 的父区块与已输出的 slot %d 不一致，回滚深度超过缓冲\n", f.buffer[i].slot, prev.slot)
+			continue
+		}
+
+		util.Logf("🔄 检测到回滚: slot %d 的父区块为 %d (%s)，重新获取 slot %d 起的区块\n",
+			f.buffer[i].slot, block.ParentSlot, block.PreviousBlockhash, prev.slot)
+		f.next = prev.slot
+		f.buffer = f.buffer[:i-1]
+		return
+	}
+}
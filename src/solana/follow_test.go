@@ -0,0 +1,128 @@
+package solana
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+func testBlock(slot, parent uint64) *model.Block {
+	return &model.Block{
+		Blockhash:         fmt.Sprintf("hash-%d", slot),
+		ParentSlot:        parent,
+		PreviousBlockhash: fmt.Sprintf("hash-%d", parent),
+	}
+}
+
+// newTestFollower 使用固定的链数据创建跟随器，chain 中不存在的 slot 视为尚不可用
+func newTestFollower(tip uint64, chain map[uint64]*model.Block, skipped map[uint64]bool, emitted *[]uint64) *chainFollower {
+	return &chainFollower{
+		opts:   FollowOptions{ReorgBuffer: 2},
+		getTip: func() (uint64, error) { return tip, nil },
+		fetch: func(slots []uint64) (map[uint64]*model.Block, map[uint64]error) {
+			blocks := make(map[uint64]*model.Block)
+			slotErrors := make(map[uint64]error)
+			for _, slot := range slots {
+				switch {
+				case chain[slot] != nil:
+					blocks[slot] = chain[slot]
+				case skipped[slot]:
+					slotErrors[slot] = &model.RPCError{Code: model.RPCErrorSlotSkipped, Message: "skipped"}
+				default:
+					slotErrors[slot] = &model.RPCError{Code: model.RPCErrorBlockNotAvailable, Message: "Block not available"}
+				}
+			}
+			return blocks, slotErrors
+		},
+		handler: func(slot uint64, block *model.Block) error {
+			*emitted = append(*emitted, slot)
+			return nil
+		},
+	}
+}
+
+func TestChainFollowerSkipsSlotsAndHoldsReorgBuffer(t *testing.T) {
+	chain := map[uint64]*model.Block{
+		100: testBlock(100, 99),
+		101: testBlock(101, 100),
+		103: testBlock(103, 101),
+		105: testBlock(105, 104),
+	}
+	var emitted []uint64
+	follower := newTestFollower(105, chain, map[uint64]bool{102: true}, &emitted)
+	follower.next = 100
+
+	result, err := follower.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.caughtUp || !result.advanced || result.fetchErr != nil {
+		t.Errorf("result = %+v, want advanced and waiting for unavailable slot 104", result)
+	}
+	if follower.next != 104 {
+		t.Errorf("next = %d, want 104", follower.next)
+	}
+	if want := []uint64{100, 101, 103}; !reflect.DeepEqual(emitted, want) {
+		t.Errorf("emitted = %v, want %v", emitted, want)
+	}
+	if len(follower.buffer) != 0 {
+		t.Errorf("buffer = %d blocks, want 0", len(follower.buffer))
+	}
+}
+
+func TestChainFollowerRefetchesOnReorg(t *testing.T) {
+	chain := map[uint64]*model.Block{
+		200: testBlock(200, 199),
+		201: testBlock(201, 200),
+	}
+	var emitted []uint64
+	follower := newTestFollower(210, chain, nil, &emitted)
+
+	// 缓冲区中的 200 属于被放弃的分叉，201 的父哈希与其不一致
+	orphan := testBlock(200, 199)
+	orphan.Blockhash = "orphan"
+	follower.buffer = []followedBlock{{slot: 200, block: orphan}, {slot: 201, block: chain[201]}}
+	follower.next = 202
+
+	follower.verifyBuffer()
+	if follower.next != 200 || len(follower.buffer) != 0 {
+		t.Fatalf("next = %d, buffer = %d blocks, want refetch from 200", follower.next, len(follower.buffer))
+	}
+
+	if _, err := follower.poll(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{200, 201}; !reflect.DeepEqual(emitted, want) {
+		t.Errorf("emitted = %v, want %v", emitted, want)
+	}
+	if follower.emitted == nil || follower.emitted.block.Blockhash != "hash-201" {
+		t.Errorf("last emitted block = %+v, want hash-201", follower.emitted)
+	}
+}
+
+func TestChainFollowerReportsStalledFetch(t *testing.T) {
+	var emitted []uint64
+	follower := newTestFollower(105, map[uint64]*model.Block{}, nil, &emitted)
+	follower.next = 104
+
+	result, err := follower.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.advanced || result.caughtUp || result.fetchErr != nil {
+		t.Errorf("result = %+v, want no progress without fetch error for unavailable block", result)
+	}
+
+	follower.fetch = func(slots []uint64) (map[uint64]*model.Block, map[uint64]error) {
+		return nil, map[uint64]error{slots[0]: &SlotError{Slot: slots[0], Kind: SlotErrorRateLimited, StatusCode: 429, Err: fmt.Errorf("too many requests")}}
+	}
+	result, err = follower.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.advanced || result.fetchErr == nil || follower.next != 104 {
+		t.Errorf("result = %+v, next = %d, want stalled at 104 with fetch error", result, follower.next)
+	}
+}
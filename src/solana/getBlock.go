@@ -2,6 +2,7 @@ package solana
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,46 +12,15 @@ import (
 	"time"
 
 	"github.com/go-solana-parse/src/model"
-	"golang.org/x/time/rate"
+	"github.com/go-solana-parse/src/util"
 )
 
-// Global HTTP client optimized for high concurrency
+// 共享的高性能HTTP客户端，所有区块获取路径复用同一个连接池
 var (
-	httpClient     *http.Client
 	clientOnce     sync.Once
-	rateLimiter    *rate.Limiter
 	highPerfClient *http.Client
 )
 
-// initHTTPClient initializes the optimized HTTP client
-func initHTTPClient() {
-	// Create rate limiter: 500 requests per second with burst capacity of 100
-	rateLimiter = rate.NewLimiter(rate.Limit(500), 100)
-
-	// Configure transport for high concurrency
-	transport := &http.Transport{
-		MaxIdleConns:        1000,             // Maximum idle connections
-		MaxIdleConnsPerHost: 100,              // Maximum idle connections per host
-		MaxConnsPerHost:     200,              // Maximum connections per host
-		IdleConnTimeout:     90 * time.Second, // How long idle connections stay open
-		DisableCompression:  false,            // Enable compression
-		DisableKeepAlives:   false,            // Enable keep-alives
-		WriteBufferSize:     32 * 1024,        // 32KB write buffer
-		ReadBufferSize:      32 * 1024,        // 32KB read buffer
-	}
-
-	httpClient = &http.Client{
-		Transport: transport,
-		Timeout:   30 * time.Second, // Total request timeout
-	}
-}
-
-// getHTTPClient returns the singleton HTTP client
-func getHTTPClient() *http.Client {
-	clientOnce.Do(initHTTPClient)
-	return httpClient
-}
-
 // getHighPerfClient 获取高性能HTTP客户端（单例）
 func getHighPerfClient() *http.Client {
 	clientOnce.Do(func() {
@@ -90,8 +60,12 @@ func GetBlockData(slotNum uint64, apiKey string) (*model.Block, error) {
 	// 使用高性能客户端
 	client := getHighPerfClient()
 
-	// 构建请求URL
-	url := fmt.Sprintf("https://mainnet.helius-rpc.com/?api-key=%s", apiKey)
+	// 从节点池选择节点
+	pool := endpointPoolFor(apiKey)
+	endpoint := pool.Pick(nil)
+	if err := endpoint.Wait(context.Background()); err != nil {
+		return nil, err
+	}
 
 	// 构建请求参数
 	params := []interface{}{
@@ -119,7 +93,7 @@ func GetBlockData(slotNum uint64, apiKey string) (*model.Block, error) {
 	}
 
 	// 创建请求
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("POST", endpoint.URL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
@@ -129,8 +103,10 @@ func GetBlockData(slotNum uint64, apiKey string) (*model.Block, error) {
 	req.Header.Set("Connection", "keep-alive") // 强制keep-alive
 
 	// 发送请求
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
+		pool.Report(endpoint, time.Since(start), false)
 		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
 	defer resp.Body.Close()
@@ -138,19 +114,24 @@ func GetBlockData(slotNum uint64, apiKey string) (*model.Block, error) {
 	// 读取响应
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		pool.Report(endpoint, time.Since(start), false)
 		return nil, fmt.Errorf("failed to read response: %v", err)
 	}
 
 	// 解析响应
 	var response model.RPCResponse
 	if err := json.Unmarshal(body, &response); err != nil {
+		pool.Report(endpoint, time.Since(start), false)
 		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
 	}
 
-	// 检查RPC错误
+	// 检查RPC错误，按批量获取的规则计入节点健康状态
 	if response.Error != nil {
-		return nil, fmt.Errorf("RPC error: %s (code: %d)", response.Error.Message, response.Error.Code)
+		err := &SlotError{Slot: slotNum, Kind: SlotErrorRPC, Code: response.Error.Code, StatusCode: resp.StatusCode, Err: response.Error}
+		pool.Report(endpoint, time.Since(start), !countsAsEndpointFailure(err))
+		return nil, err
 	}
+	pool.Report(endpoint, time.Since(start), true)
 
 	// 检查是否找到区块
 	if response.Result == nil {
@@ -171,79 +152,176 @@ type BatchGetBlockResponse []model.GetBlockResponse
 // apiKey: Helius API密钥
 // batchSize: 每次批量请求的数量(建议10-20)
 func GetMultipleBlocksData(slotNums []uint64, apiKey string, batchSize int) map[uint64]*model.Block {
-	results := make(map[uint64]*model.Block)
-	client := getHighPerfClient()
+	results, _ := GetMultipleBlocksDataWithErrors(slotNums, apiKey, batchSize)
+	return results
+}
 
-	wg := sync.WaitGroup{}
-	var resultsMutex sync.Mutex // 🔒 添加互斥锁保护map写入
+// GetMultipleBlocksDataWithErrors 批量获取多个区块数据，同时返回获取失败的slot及原因
+// RPC 返回的错误为 *model.RPCError，可据此区分 slot 被跳过等永久性错误
+func GetMultipleBlocksDataWithErrors(slotNums []uint64, apiKey string, batchSize int) (map[uint64]*model.Block, map[uint64]error) {
+	return getMultipleBlocks(slotNums, apiKey, batchSize, "")
+}
 
-	// 分批处理
-	for i := 0; i < len(slotNums); i += batchSize {
-		end := i + batchSize
-		if end > len(slotNums) {
-			end = len(slotNums)
-		}
+// getMultipleBlocks 按指定确认级别批量获取区块，commitment 为空时使用节点默认级别（finalized）
+// 使用共享的批量获取器，临时性错误按默认配置重试，仍失败的 slot 由调用方决定是否再试
+func getMultipleBlocks(slotNums []uint64, apiKey string, batchSize int, commitment string) (map[uint64]*model.Block, map[uint64]error) {
+	return CollectBlocks(context.Background(), sharedBlockFetcher(apiKey, batchSize, commitment, DefaultBatchRPCConfig().RetryAttempts), slotNums)
+}
 
-		batch := slotNums[i:end]
+var (
+	sharedFetchersMu sync.Mutex
+	sharedFetchers   = map[string]*BatchRPCFetcher{}
+	sharedLimiters   = map[string]*AdaptiveLimiter{}
+)
 
-		wg.Add(1)
-		go func(batchSlots []uint64) { // 🔒 传递参数避免闭包问题
-			defer wg.Done()
+// sharedBlockFetcher 按参数复用批量获取器，同一 API key 的获取器共享节点池与自适应限速
+// 多次调用（重试、跟随等）因此共用 AIMD 限速状态与节点健康状态
+func sharedBlockFetcher(apiKey string, batchSize int, commitment string, retryAttempts int) *BatchRPCFetcher {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
 
-			batchResults := processBatch(batchSlots, apiKey, client)
+	sharedFetchersMu.Lock()
+	defer sharedFetchersMu.Unlock()
 
-			// 🔒 加锁保护并发写入
-			resultsMutex.Lock()
-			for slot, block := range batchResults {
+	key := fmt.Sprintf("%s|%s|%d|%d", apiKey, commitment, batchSize, retryAttempts)
+	if fetcher, ok := sharedFetchers[key]; ok {
+		return fetcher
+	}
+
+	batchConfig := DefaultBatchRPCConfig()
+	batchConfig.APIKey = apiKey
+	batchConfig.Commitment = commitment
+	batchConfig.MaxBatchSize = batchSize
+	batchConfig.RetryAttempts = retryAttempts
+	fetcher := NewBatchRPCFetcher(batchConfig)
+
+	if limiter, ok := sharedLimiters[apiKey]; ok {
+		fetcher.rateLimiter = limiter
+	} else {
+		sharedLimiters[apiKey] = fetcher.rateLimiter
+	}
+	sharedFetchers[key] = fetcher
+	return fetcher
+}
+
+// fetchBatchWithFailover 从节点池选择节点获取一批区块，部分失败时剩余 slot 换其他节点重试
+// slot 被跳过属于永久性错误，不会重试也不计入节点失败；区块尚未产出（-32004）会换节点重试，但不计入节点失败
+// requestTimeout 为单个节点请求的超时时间，0 表示不额外限制
+func fetchBatchWithFailover(ctx context.Context, pool *EndpointPool, slotNums []uint64, client *http.Client, commitment string, filter TransactionFilter, requestTimeout time.Duration) (map[uint64]*model.Block, map[uint64]error) {
+	results := make(map[uint64]*model.Block)
+	slotErrors := make(map[uint64]error)
+
+	maxAttempts := pool.Size()
+	if maxAttempts > 3 {
+		maxAttempts = 3
+	}
+
+	tried := make(map[*Endpoint]bool)
+	remaining := slotNums
+	for attempt := 0; attempt < maxAttempts && len(remaining) > 0; attempt++ {
+		endpoint := pool.Pick(tried)
+		tried[endpoint] = true
+		if err := endpoint.Wait(ctx); err != nil {
+			for _, slot := range remaining {
+				slotErrors[slot] = err
+			}
+			break
+		}
+
+		requestCtx, cancel := ctx, context.CancelFunc(func() {})
+		if requestTimeout > 0 {
+			requestCtx, cancel = context.WithTimeout(ctx, requestTimeout)
+		}
+		start := time.Now()
+		batchResults, batchErrors := processBatch(requestCtx, remaining, endpoint.URL, client, commitment, filter)
+		cancel()
+
+		var retry []uint64
+		healthy := true
+		for _, slot := range remaining {
+			if block, ok := batchResults[slot]; ok {
 				results[slot] = block
+				delete(slotErrors, slot)
+				continue
+			}
+			err := batchErrors[slot]
+			slotErrors[slot] = err
+			if IsSlotSkipped(err) {
+				continue
 			}
-			resultsMutex.Unlock()
-		}(batch) // 🔒 传递batch参数
+			if countsAsEndpointFailure(err) {
+				healthy = false
+			}
+			retry = append(retry, slot)
+		}
+		pool.Report(endpoint, time.Since(start), healthy)
+
+		if len(retry) > 0 && attempt+1 < maxAttempts {
+			util.Logf("🔁 %d 个slot在节点 %s 获取失败，换节点重试\n", len(retry), redactURL(endpoint.URL))
+		}
+		remaining = retry
 	}
 
-	wg.Wait()
+	return results, slotErrors
+}
 
-	return results
+// countsAsEndpointFailure 判断 slot 获取错误是否计入节点失败：slot 被跳过与区块尚未产出不计入
+func countsAsEndpointFailure(err error) bool {
+	return !IsSlotSkipped(err) && !IsBlockNotAvailable(err)
 }
 
-// processBatch 处理一批区块请求
-func processBatch(slotNums []uint64, apiKey string, client *http.Client) map[uint64]*model.Block {
+// processBatch 处理一批区块请求，返回成功的区块与失败slot的错误
+// 失败的slot均记录 *SlotError，批量响应按请求ID匹配slot（响应顺序不保证与请求一致）
+// filter 不为空时，未通过过滤的交易在解码时丢弃
+func processBatch(ctx context.Context, slotNums []uint64, url string, client *http.Client, commitment string, filter TransactionFilter) (map[uint64]*model.Block, map[uint64]error) {
+	results := make(map[uint64]*model.Block)
+	slotErrors := make(map[uint64]error)
 	if len(slotNums) == 0 {
-		return make(map[uint64]*model.Block)
+		return results, slotErrors
+	}
+
+	// 整批失败时所有slot记录同一类错误
+	failAll := func(kind SlotErrorKind, code, statusCode int, err error) (map[uint64]*model.Block, map[uint64]error) {
+		for _, slotNum := range slotNums {
+			slotErrors[slotNum] = &SlotError{Slot: slotNum, Kind: kind, Code: code, StatusCode: statusCode, Err: err}
+		}
+		return results, slotErrors
 	}
 
-	// 构建批量请求
+	// 构建批量请求，ID从1开始对应slot下标
 	var batchRequest BatchGetBlockRequest
+	slotByID := make(map[int]uint64, len(slotNums))
 	for i, slotNum := range slotNums {
+		blockConfig := map[string]interface{}{
+			"maxSupportedTransactionVersion": 0,
+			"transactionDetails":             "full",
+			"encoding":                       "json",
+			"rewards":                        false,
+		}
+		if commitment != "" {
+			blockConfig["commitment"] = commitment
+		}
 		request := model.RPCRequest{
 			JSONRPC: "2.0",
 			ID:      i + 1, // 每个请求需要唯一ID
 			Method:  "getBlock",
-			Params: []interface{}{
-				slotNum,
-				map[string]interface{}{
-					"maxSupportedTransactionVersion": 0,
-					"transactionDetails":             "full",
-					"encoding":                       "json",
-					"rewards":                        false,
-				},
-			},
+			Params:  []interface{}{slotNum, blockConfig},
 		}
 		batchRequest = append(batchRequest, request)
+		slotByID[request.ID] = slotNum
 	}
 
 	// 发送批量请求
-	url := fmt.Sprintf("https://mainnet.helius-rpc.com/?api-key=%s", apiKey)
-
 	jsonData, err := json.Marshal(batchRequest)
 
 	if err != nil {
-		return make(map[uint64]*model.Block)
+		return failAll(SlotErrorDecode, 0, 0, fmt.Errorf("failed to marshal request: %v", err))
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return make(map[uint64]*model.Block)
+		return failAll(SlotErrorTransport, 0, 0, fmt.Errorf("failed to create request: %v", err))
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -251,43 +329,81 @@ func processBatch(slotNums []uint64, apiKey string, client *http.Client) map[uin
 
 	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Printf("发送批量请求失败: %v\n", err)
-		return make(map[uint64]*model.Block)
+		util.Logf("发送批量请求失败: %v\n", err)
+		return failAll(SlotErrorTransport, 0, 0, fmt.Errorf("failed to send request: %v", err))
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("读取批量响应失败: %v\n", err)
-		return make(map[uint64]*model.Block)
+	if resp.StatusCode != http.StatusOK {
+		// 读取少量响应体以便连接复用
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
 	}
-
-	// 解析批量响应
-	var batchResponse BatchGetBlockResponse
-	if err := json.Unmarshal(body, &batchResponse); err != nil {
-		fmt.Printf("解析批量响应失败: %v\n", err)
-		return make(map[uint64]*model.Block)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		for _, slotNum := range slotNums {
+			slotErrors[slotNum] = &SlotError{Slot: slotNum, Kind: SlotErrorRateLimited, StatusCode: resp.StatusCode, RetryAfter: retryAfter,
+				Err: fmt.Errorf("rate limited by %s", redactURL(url))}
+		}
+		return results, slotErrors
+	}
+	if resp.StatusCode != http.StatusOK {
+		return failAll(SlotErrorHTTPStatus, 0, resp.StatusCode, fmt.Errorf("unexpected status: %s", resp.Status))
 	}
 
-	// 处理结果
-	results := make(map[uint64]*model.Block)
-	for i, response := range batchResponse {
-		if i >= len(slotNums) {
-			continue
+	// 流式解析批量响应，按ID匹配结果，交易逐笔过滤
+	body := &readErrorRecorder{r: resp.Body}
+	single, err := decodeBatchResponse(body, filter, func(response model.GetBlockResponse) {
+		slotNum, ok := slotByID[response.ID]
+		if !ok {
+			return
 		}
 
-		slotNum := slotNums[i]
-
 		if response.Error != nil {
-			continue
+			slotErrors[slotNum] = &SlotError{Slot: slotNum, Kind: SlotErrorRPC, Code: response.Error.Code, StatusCode: resp.StatusCode, Err: response.Error}
+			return
 		}
 
 		if response.Result != nil {
 			results[slotNum] = response.Result
 		}
+	})
+
+	// 节点对整批请求返回单个错误对象（而非数组）时，所有slot记录该RPC错误
+	if single != nil && single.Error != nil {
+		return failAll(SlotErrorRPC, single.Error.Code, resp.StatusCode, single.Error)
 	}
 
-	return results
+	// 解析中断时已解析的结果保留，其余slot记录读取或解析错误
+	if err != nil {
+		kind := SlotErrorDecode
+		if body.err != nil {
+			kind = SlotErrorTransport
+			util.Logf("读取批量响应失败: %v\n", err)
+		} else {
+			util.Logf("解析批量响应失败: %v\n", err)
+		}
+		for _, slotNum := range slotNums {
+			if _, ok := results[slotNum]; ok {
+				continue
+			}
+			if _, ok := slotErrors[slotNum]; !ok {
+				slotErrors[slotNum] = &SlotError{Slot: slotNum, Kind: kind, StatusCode: resp.StatusCode, Err: fmt.Errorf("failed to decode response: %v", err)}
+			}
+		}
+		return results, slotErrors
+	}
+
+	// 响应中缺失的slot
+	for _, slotNum := range slotNums {
+		if _, ok := results[slotNum]; ok {
+			continue
+		}
+		if _, ok := slotErrors[slotNum]; !ok {
+			slotErrors[slotNum] = &SlotError{Slot: slotNum, Kind: SlotErrorMissing, StatusCode: resp.StatusCode, Err: fmt.Errorf("block not found for slot %d", slotNum)}
+		}
+	}
+
+	return results, slotErrors
 }
 
 func BatchGetBlockDataFastV2(startSlot, endSlot uint64, apiKey string, batchSize, maxConcurrency int) map[uint64]*model.Block {
@@ -309,7 +425,7 @@ func BatchGetBlockDataFastV2(startSlot, endSlot uint64, apiKey string, batchSize
 		batches = append(batches, allSlots[i:end])
 	}
 
-	fmt.Printf("📦 创建 %d 个批次，每批 %d 个slot\n", len(batches), batchSize)
+	util.Logf("📦 创建 %d 个批次，每批 %d 个slot\n", len(batches), batchSize)
 
 	startTime := time.Now()
 
@@ -322,18 +438,18 @@ func BatchGetBlockDataFastV2(startSlot, endSlot uint64, apiKey string, batchSize
 		for slot, block := range batchResults {
 			results[slot] = block
 		}
-		fmt.Printf("✅ 批次 %d 完成: %d/%d 个slot成功\n",
+		util.Logf("✅ 批次 %d 完成: %d/%d 个slot成功\n",
 			i, len(batchResults), len(batch))
 	}
 
 	elapsed := time.Since(startTime)
 
-	fmt.Printf("\n🎯 批量请求完成!\n")
-	fmt.Printf("总耗时: %v\n", elapsed)
-	fmt.Printf("成功率: %.2f%% (%d/%d)\n",
+	util.Logf("\n🎯 批量请求完成!\n")
+	util.Logf("总耗时: %v\n", elapsed)
+	util.Logf("成功率: %.2f%% (%d/%d)\n",
 		float64(len(results))/float64(len(allSlots))*100, len(results), len(allSlots))
-	fmt.Printf("平均速度: %.2f blocks/second\n", float64(len(results))/elapsed.Seconds())
-	fmt.Printf("HTTP请求数: %d (vs %d)\n", len(batches), len(allSlots))
+	util.Logf("平均速度: %.2f blocks/second\n", float64(len(results))/elapsed.Seconds())
+	util.Logf("HTTP请求数: %d (vs %d)\n", len(batches), len(allSlots))
 
 	return results
 }
@@ -0,0 +1,151 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+func TestProcessBatchMatchesResponsesByID(t *testing.T) {
+	// 响应倒序返回，slot 21 被跳过，slot 22 的响应缺失
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []model.RPCRequest
+		json.NewDecoder(r.Body).Decode(&requests)
+		var responses []model.GetBlockResponse
+		for i := len(requests) - 1; i >= 0; i-- {
+			slot := uint64(requests[i].Params[0].(float64))
+			response := model.GetBlockResponse{JSONRPC: "2.0", ID: requests[i].ID}
+			switch slot {
+			case 21:
+				response.Error = &model.RPCError{Code: model.RPCErrorLongTermStorageSkipped, Message: "missing in long-term storage"}
+			case 22:
+				continue
+			default:
+				response.Result = &model.Block{Blockhash: fmt.Sprintf("hash-%d", slot)}
+			}
+			responses = append(responses, response)
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	results, slotErrors := processBatch(context.Background(), []uint64{20, 21, 22, 23}, server.URL, http.DefaultClient, "", nil)
+	if results[20] == nil || results[20].Blockhash != "hash-20" || results[23] == nil || results[23].Blockhash != "hash-23" {
+		t.Fatalf("results = %v, want slots 20 and 23 matched by id", results)
+	}
+	if slotErr, ok := slotErrors[21].(*SlotError); !ok || slotErr.Kind != SlotErrorRPC || slotErr.Code != model.RPCErrorLongTermStorageSkipped {
+		t.Errorf("slot 21 error = %v, want rpc -32009", slotErrors[21])
+	}
+	if slotErr, ok := slotErrors[22].(*SlotError); !ok || slotErr.Kind != SlotErrorMissing {
+		t.Errorf("slot 22 error = %v, want missing", slotErrors[22])
+	}
+}
+
+func TestProcessBatchClassifiesWholeBatchFailures(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler http.HandlerFunc
+		kind    SlotErrorKind
+		code    int
+	}{
+		{"rate limited", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}, SlotErrorRateLimited, 0},
+		{"http status", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}, SlotErrorHTTPStatus, 0},
+		{"single error object", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32005,"message":"Node is behind"},"id":null}`))
+		}, SlotErrorRPC, -32005},
+		{"decode", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`not json`))
+		}, SlotErrorDecode, 0},
+	}
+
+	for _, c := range cases {
+		server := httptest.NewServer(c.handler)
+		_, slotErrors := processBatch(context.Background(), []uint64{1, 2}, server.URL, http.DefaultClient, "", nil)
+		server.Close()
+
+		for _, slot := range []uint64{1, 2} {
+			slotErr, ok := slotErrors[slot].(*SlotError)
+			if !ok || slotErr.Kind != c.kind || slotErr.Code != c.code {
+				t.Errorf("%s: slot %d error = %v, want kind %s code %d", c.name, slot, slotErrors[slot], c.kind, c.code)
+			}
+		}
+	}
+
+	// 连接失败
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+	_, slotErrors := processBatch(context.Background(), []uint64{1}, url, http.DefaultClient, "", nil)
+	if slotErr, ok := slotErrors[1].(*SlotError); !ok || slotErr.Kind != SlotErrorTransport {
+		t.Errorf("closed server: error = %v, want transport", slotErrors[1])
+	}
+}
+
+func TestSharedBlockFetcherReusesLimiterAndPool(t *testing.T) {
+	first := sharedBlockFetcher("shared-test-key", 10, "", 3)
+	if again := sharedBlockFetcher("shared-test-key", 10, "", 3); again != first {
+		t.Error("expected the same fetcher for the same parameters")
+	}
+	if first.rateLimiter == nil || first.config.RetryAttempts != 3 {
+		t.Errorf("fetcher config = %+v, want adaptive limiter and 3 retries", first.config)
+	}
+
+	follow := sharedBlockFetcher("shared-test-key", 20, CommitmentConfirmed, 0)
+	if follow == first || follow.rateLimiter != first.rateLimiter || follow.pool != first.pool {
+		t.Error("fetchers for the same api key should share the rate limiter and endpoint pool")
+	}
+}
+
+func TestGetBlockDataClassifiesRPCErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request model.RPCRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		response := model.GetBlockResponse{JSONRPC: "2.0", ID: request.ID}
+		if slot := uint64(request.Params[0].(float64)); slot == 5 {
+			response.Error = &model.RPCError{Code: model.RPCErrorSlotSkipped, Message: "skipped"}
+		} else {
+			response.Error = &model.RPCError{Code: -32005, Message: "node is behind"}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	previous := config.SvcConfig.Solana.Endpoints
+	config.SvcConfig.Solana.Endpoints = []config.EndpointConfig{{URL: server.URL}}
+	endpointPoolsMu.Lock()
+	delete(endpointPools, "config")
+	endpointPoolsMu.Unlock()
+	defer func() {
+		config.SvcConfig.Solana.Endpoints = previous
+		endpointPoolsMu.Lock()
+		delete(endpointPools, "config")
+		endpointPoolsMu.Unlock()
+	}()
+
+	_, err := GetBlockData(5, "")
+	var rpcErr *model.RPCError
+	if !IsSlotSkipped(err) || !errors.As(err, &rpcErr) {
+		t.Errorf("GetBlockData(5) error = %v, want skipped *model.RPCError", err)
+	}
+	if stats := endpointPoolFor("").Stats(); stats[0].Failures != 0 {
+		t.Errorf("stats = %+v, want skipped slot not counted as failure", stats)
+	}
+
+	if _, err := GetBlockData(6, ""); !errors.As(err, &rpcErr) || rpcErr.Code != -32005 {
+		t.Errorf("GetBlockData(6) error = %v, want rpc -32005", err)
+	}
+	if stats := endpointPoolFor("").Stats(); stats[0].Failures != 1 {
+		t.Errorf("stats = %+v, want node error counted as failure", stats)
+	}
+}
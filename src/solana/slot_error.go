@@ -0,0 +1,87 @@
+package solana
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-solana-parse/src/model"
+)
+
+// SlotErrorKind 区块获取失败的类型
+type SlotErrorKind string
+
+const (
+	SlotErrorRPC         SlotErrorKind = "rpc"          // JSON-RPC 返回错误，Code 为错误码
+	SlotErrorTransport   SlotErrorKind = "transport"    // 建立连接、发送请求或读取响应失败
+	SlotErrorHTTPStatus  SlotErrorKind = "http_status"  // HTTP 状态码非 200（429 除外）
+	SlotErrorRateLimited SlotErrorKind = "rate_limited" // HTTP 429，被节点限流
+	SlotErrorDecode      SlotErrorKind = "decode"       // 请求编码或响应解析失败
+	SlotErrorMissing     SlotErrorKind = "missing"      // 批量响应中没有该 slot 的结果
+)
+
+// SlotError 单个 slot 获取失败的原因
+type SlotError struct {
+	Slot       uint64
+	Kind       SlotErrorKind
+	Code       int           // Kind 为 rpc 时的 JSON-RPC 错误码
+	StatusCode int           // HTTP 状态码，未收到响应时为 0
+	RetryAfter time.Duration // 节点通过 Retry-After 要求的等待时长
+	Err        error
+}
+
+func (e *SlotError) Error() string {
+	switch e.Kind {
+	case SlotErrorRPC:
+		return fmt.Sprintf("slot %d: %v", e.Slot, e.Err)
+	case SlotErrorHTTPStatus, SlotErrorRateLimited:
+		return fmt.Sprintf("slot %d: %s %d: %v", e.Slot, e.Kind, e.StatusCode, e.Err)
+	default:
+		return fmt.Sprintf("slot %d: %s: %v", e.Slot, e.Kind, e.Err)
+	}
+}
+
+// Unwrap 返回底层错误，RPC 错误可通过 errors.As 取得 *model.RPCError
+func (e *SlotError) Unwrap() error {
+	return e.Err
+}
+
+// Reason 简短的失败原因，用于失败区块文件
+func (e *SlotError) Reason() string {
+	switch e.Kind {
+	case SlotErrorRPC:
+		var rpcErr *model.RPCError
+		if errors.As(e.Err, &rpcErr) {
+			return fmt.Sprintf("rpc %d: %s", e.Code, rpcErr.Message)
+		}
+		return fmt.Sprintf("rpc %d: %v", e.Code, e.Err)
+	case SlotErrorHTTPStatus, SlotErrorRateLimited:
+		return fmt.Sprintf("%s %d", e.Kind, e.StatusCode)
+	default:
+		return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+	}
+}
+
+// IsSlotSkipped 判断错误是否表示 slot 被跳过（-32007，重试也无法获取的永久性错误）
+func IsSlotSkipped(err error) bool {
+	var rpcErr *model.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.IsSlotSkipped()
+}
+
+// IsBlockNotAvailable 判断错误是否表示区块尚未产出（-32004），稍后重试即可获取
+func IsBlockNotAvailable(err error) bool {
+	var rpcErr *model.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == model.RPCErrorBlockNotAvailable
+}
+
+// SlotErrorReason 返回错误的简短原因，非 *SlotError 时返回错误文本
+func SlotErrorReason(err error) string {
+	if err == nil {
+		return ""
+	}
+	var slotErr *SlotError
+	if errors.As(err, &slotErr) {
+		return slotErr.Reason()
+	}
+	return err.Error()
+}
@@ -0,0 +1,191 @@
+package solana
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+// TransactionFilter 交易过滤函数，返回 false 的交易在解码时直接丢弃，不会保留在区块中
+type TransactionFilter func(tx *model.TransactionInfo) bool
+
+// FilterProgramsAllDex filter.programs 中表示全部 DEX_PROGRAMS 的取值
+const FilterProgramsAllDex = "dex"
+
+// AllOf 组合多个过滤条件，全部通过才保留交易
+func AllOf(filters ...TransactionFilter) TransactionFilter {
+	return func(tx *model.TransactionInfo) bool {
+		for _, filter := range filters {
+			if !filter(tx) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ProgramsAnyOf 交易账户（含地址查找表加载的账户）中包含任一指定程序
+func ProgramsAnyOf(programIDs ...string) TransactionFilter {
+	programs := toSet(programIDs)
+	return func(tx *model.TransactionInfo) bool {
+		for _, account := range tx.Transaction.Message.AccountKeys {
+			if programs[account] {
+				return true
+			}
+		}
+		if tx.Meta != nil && tx.Meta.LoadedAddresses != nil {
+			for _, account := range tx.Meta.LoadedAddresses.Writable {
+				if programs[account] {
+					return true
+				}
+			}
+			for _, account := range tx.Meta.LoadedAddresses.Readonly {
+				if programs[account] {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// ExcludeFailed 排除执行失败（Meta.Err 不为空）的交易
+func ExcludeFailed(tx *model.TransactionInfo) bool {
+	return tx.Meta == nil || tx.Meta.Err == nil
+}
+
+// MintAllowlist 交易的代币余额变化中至少涉及一个指定 mint
+func MintAllowlist(mints ...string) TransactionFilter {
+	allowed := toSet(mints)
+	return func(tx *model.TransactionInfo) bool {
+		found := false
+		forEachMint(tx, func(mint string) {
+			found = found || allowed[mint]
+		})
+		return found
+	}
+}
+
+// MintDenylist 交易的代币余额变化中涉及任一指定 mint 时丢弃
+func MintDenylist(mints ...string) TransactionFilter {
+	denied := toSet(mints)
+	return func(tx *model.TransactionInfo) bool {
+		found := false
+		forEachMint(tx, func(mint string) {
+			found = found || denied[mint]
+		})
+		return !found
+	}
+}
+
+// SignerAllowlist 交易签名者中至少包含一个指定地址
+func SignerAllowlist(signers ...string) TransactionFilter {
+	allowed := toSet(signers)
+	return func(tx *model.TransactionInfo) bool {
+		accountKeys := tx.Transaction.Message.AccountKeys
+		numSigners := tx.Transaction.Message.Header.NumRequiredSignatures
+		if numSigners > len(accountKeys) {
+			numSigners = len(accountKeys)
+		}
+		for _, signer := range accountKeys[:numSigners] {
+			if allowed[signer] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DefaultTransactionFilter 默认过滤器：只保留涉及 Token / Token-2022 程序的交易
+func DefaultTransactionFilter() TransactionFilter {
+	return ProgramsAnyOf(config.TOKEN_PROGRAM_ID, config.TOKEN_2022_PROGRAM_ID)
+}
+
+// NewTransactionFilter 根据配置文件 filter 段构建过滤器
+// programs 可以是 DEX_PROGRAMS 中的名称、程序地址或 "dex"（全部 DEX 程序），为空时使用 Token / Token-2022 程序
+func NewTransactionFilter(cfg config.FilterConfig) (TransactionFilter, error) {
+	programIDs, err := resolveFilterPrograms(cfg.Programs)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []TransactionFilter{ProgramsAnyOf(programIDs...)}
+	if cfg.ExcludeFailed {
+		filters = append(filters, ExcludeFailed)
+	}
+	if len(cfg.MintAllowlist) > 0 {
+		filters = append(filters, MintAllowlist(cfg.MintAllowlist...))
+	}
+	if len(cfg.MintDenylist) > 0 {
+		filters = append(filters, MintDenylist(cfg.MintDenylist...))
+	}
+	if len(cfg.SignerAllowlist) > 0 {
+		filters = append(filters, SignerAllowlist(cfg.SignerAllowlist...))
+	}
+	return AllOf(filters...), nil
+}
+
+// resolveFilterPrograms 将 filter.programs 解析为程序地址
+func resolveFilterPrograms(entries []string) ([]string, error) {
+	if len(entries) == 0 {
+		return []string{config.TOKEN_PROGRAM_ID, config.TOKEN_2022_PROGRAM_ID}, nil
+	}
+
+	var programIDs []string
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case strings.EqualFold(entry, FilterProgramsAllDex):
+			programIDs = append(programIDs, config.GetAllProgramIDs()...)
+		case config.DEX_PROGRAMS[strings.ToUpper(entry)].ID != "":
+			programIDs = append(programIDs, config.DEX_PROGRAMS[strings.ToUpper(entry)].ID)
+		case isProgramAddress(entry):
+			programIDs = append(programIDs, entry)
+		default:
+			names := make([]string, 0, len(config.DEX_PROGRAMS))
+			for name := range config.DEX_PROGRAMS {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("未知的程序: %q（可用 %s、程序地址或 %s）", entry, FilterProgramsAllDex, strings.Join(names, ", "))
+		}
+	}
+	return programIDs, nil
+}
+
+// isProgramAddress 粗略判断是否为 base58 编码的地址
+func isProgramAddress(value string) bool {
+	if len(value) < 32 || len(value) > 44 {
+		return false
+	}
+	for _, c := range value {
+		if !strings.ContainsRune("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// forEachMint 遍历交易前后代币余额中的 mint
+func forEachMint(tx *model.TransactionInfo, fn func(mint string)) {
+	if tx.Meta == nil {
+		return
+	}
+	for _, balance := range tx.Meta.PreTokenBalances {
+		fn(balance.Mint)
+	}
+	for _, balance := range tx.Meta.PostTokenBalances {
+		fn(balance.Mint)
+	}
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,91 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/go-solana-parse/src/config"
+	"github.com/go-solana-parse/src/model"
+)
+
+const (
+	testSigner = "Signer1111111111111111111111111111111111111"
+	testMint   = "Mint111111111111111111111111111111111111111"
+)
+
+func newFilterTestTx(accountKeys []string, loaded []string, mint string, failed bool) *model.TransactionInfo {
+	tx := &model.TransactionInfo{Meta: &model.TransactionMeta{}}
+	tx.Transaction.Message.AccountKeys = accountKeys
+	tx.Transaction.Message.Header.NumRequiredSignatures = 1
+	if loaded != nil {
+		tx.Meta.LoadedAddresses = &model.LoadedAddresses{Readonly: loaded}
+	}
+	if mint != "" {
+		tx.Meta.PostTokenBalances = []model.TokenBalance{{Mint: mint}}
+	}
+	if failed {
+		tx.Meta.Err = map[string]interface{}{"InstructionError": []interface{}{0, "Custom"}}
+	}
+	return tx
+}
+
+func TestDefaultTransactionFilterMatchesToken2022AndLookupTables(t *testing.T) {
+	filter := DefaultTransactionFilter()
+
+	cases := []struct {
+		name string
+		tx   *model.TransactionInfo
+		want bool
+	}{
+		{"token program", newFilterTestTx([]string{testSigner, config.TOKEN_PROGRAM_ID}, nil, "", false), true},
+		{"token-2022", newFilterTestTx([]string{testSigner, config.TOKEN_2022_PROGRAM_ID}, nil, "", false), true},
+		{"loaded from lookup table", newFilterTestTx([]string{testSigner}, []string{config.TOKEN_PROGRAM_ID}, "", false), true},
+		{"vote", newFilterTestTx([]string{testSigner, "Vote111111111111111111111111111111111111111"}, nil, "", false), false},
+	}
+	for _, c := range cases {
+		if got := filter(c.tx); got != c.want {
+			t.Errorf("%s: filter() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNewTransactionFilterCombinesConditions(t *testing.T) {
+	raydium := config.DEX_PROGRAMS["RAYDIUM_V4"].ID
+	filter, err := NewTransactionFilter(config.FilterConfig{
+		Programs:        []string{"raydium_v4"},
+		ExcludeFailed:   true,
+		MintAllowlist:   []string{testMint},
+		SignerAllowlist: []string{testSigner},
+	})
+	if err != nil {
+		t.Fatalf("NewTransactionFilter() error = %v", err)
+	}
+
+	cases := []struct {
+		name string
+		tx   *model.TransactionInfo
+		want bool
+	}{
+		{"all conditions", newFilterTestTx([]string{testSigner, raydium}, nil, testMint, false), true},
+		{"failed", newFilterTestTx([]string{testSigner, raydium}, nil, testMint, true), false},
+		{"other mint", newFilterTestTx([]string{testSigner, raydium}, nil, "OtherMint", false), false},
+		{"other signer", newFilterTestTx([]string{"OtherSigner", testSigner, raydium}, nil, testMint, false), false},
+		{"token program only", newFilterTestTx([]string{testSigner, config.TOKEN_PROGRAM_ID}, nil, testMint, false), false},
+	}
+	for _, c := range cases {
+		if got := filter(c.tx); got != c.want {
+			t.Errorf("%s: filter() = %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	denylist, err := NewTransactionFilter(config.FilterConfig{MintDenylist: []string{testMint}})
+	if err != nil {
+		t.Fatalf("NewTransactionFilter() error = %v", err)
+	}
+	if denylist(newFilterTestTx([]string{config.TOKEN_PROGRAM_ID}, nil, testMint, false)) {
+		t.Errorf("mint denylist kept a transaction touching a denied mint")
+	}
+
+	if _, err := NewTransactionFilter(config.FilterConfig{Programs: []string{"not-a-program"}}); err == nil {
+		t.Errorf("NewTransactionFilter() with unknown program error = nil, want error")
+	}
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Index = func() [256]int {
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		index[base58Alphabet[i]] = i
+	}
+	return index
+}()
+
+// Base58Decode 解码 base58 字符串（Solana 指令数据与公钥使用的编码）
+func Base58Decode(s string) ([]byte, error) {
+	if len(s) == 0 {
+		return []byte{}, nil
+	}
+
+	// 前导 '1' 对应前导 0 字节
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+
+	// log(58) / log(256) ≈ 0.733，预留足够空间
+	size := (len(s)-zeros)*733/1000 + 1
+	buf := make([]byte, size)
+	length := 0
+
+	for i := zeros; i < len(s); i++ {
+		carry := base58Index[s[i]]
+		if carry < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q at position %d", s[i], i)
+		}
+		j := 0
+		for k := size - 1; (carry != 0 || j < length) && k >= 0; k-- {
+			carry += 58 * int(buf[k])
+			buf[k] = byte(carry % 256)
+			carry /= 256
+			j++
+		}
+		if carry != 0 {
+			return nil, errors.New("base58 decode overflow")
+		}
+		length = j
+	}
+
+	result := make([]byte, zeros+length)
+	copy(result[zeros:], buf[size-length:])
+	return result, nil
+}
+
+// Base58Encode 将字节编码为 base58 字符串
+func Base58Encode(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) ≈ 1.366
+	size := (len(data)-zeros)*138/100 + 1
+	buf := make([]byte, size)
+	length := 0
+
+	for i := zeros; i < len(data); i++ {
+		carry := int(data[i])
+		j := 0
+		for k := size - 1; (carry != 0 || j < length) && k >= 0; k-- {
+			carry += 256 * int(buf[k])
+			buf[k] = byte(carry % 58)
+			carry /= 58
+			j++
+		}
+		length = j
+	}
+
+	result := make([]byte, zeros+length)
+	for i := 0; i < zeros; i++ {
+		result[i] = '1'
+	}
+	for i, b := range buf[size-length:] {
+		result[zeros+i] = base58Alphabet[b]
+	}
+	return string(result)
+}
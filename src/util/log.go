@@ -0,0 +1,16 @@
+package util
+
+import (
+	"fmt"
+	"os"
+)
+
+// Logf 输出进度与诊断信息到 stderr，stdout 只用于解析结果输出（sink stdout）
+func Logf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format, args...)
+}
+
+// Logln 同 Logf，按 fmt.Println 的格式输出
+func Logln(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+}
@@ -3,42 +3,54 @@ package util
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 )
 
+// HTTPStatusError 响应状态码非 200
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("resp.StatusCode not ok:%d", e.StatusCode)
+}
+
+// postClient PostReq 共用的 HTTP 客户端，复用连接
+var postClient = &http.Client{
+	Timeout: time.Second * time.Duration(100),
+}
+
 func PostReq(urlStr string, data interface{}) ([]byte, error) {
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
+	return PostJSON(urlStr, dataBytes)
+}
 
-	req, err := http.NewRequest("POST", urlStr, bytes.NewBuffer(dataBytes))
+// PostJSON 发送已编码的 JSON 请求体，用于同一请求体需要发往多个地址的场景
+func PostJSON(urlStr string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest("POST", urlStr, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := http.Client{
-		Timeout: time.Second * time.Duration(100),
-	}
-	resp, err := client.Do(req)
+	resp, err := postClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(fmt.Sprintf("resp.StatusCode not ok:%d", resp.StatusCode))
-	}
+	defer resp.Body.Close()
 
-	content, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode != http.StatusOK {
+		// 读完响应体以便连接复用
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode}
 	}
-	defer resp.Body.Close()
 
-	return content, nil
+	return io.ReadAll(resp.Body)
 }
@@ -1,7 +1,7 @@
 pkill -f go-report-processor
 
-go build -o go-report-processor src/main.go
+go build -o go-report-processor ./src
 
 rm nohup.out
 
-nohup ./go-report-processor > nohup.out 2>&1 &
\ No newline at end of file
+nohup ./go-report-processor report > nohup.out 2>&1 &
\ No newline at end of file
@@ -6,7 +6,7 @@
 echo "🛑 正在停止所有Go处理进程..."
 
 # 查找所有相关进程
-GO_PROCESSES=$(pgrep -f "go run ./src/main.go")
+GO_PROCESSES=$(pgrep -f "go run ./src scan")
 
 if [ -z "$GO_PROCESSES" ]; then
     echo "✅ 没有发现运行中的Go进程"
@@ -17,28 +17,28 @@ echo "🔍 发现 $(echo "$GO_PROCESSES" | wc -l) 个运行中的进程"
 
 # 优雅停止
 echo "⏳ 尝试优雅停止进程..."
-pkill -TERM -f "go run ./src/main.go"
+pkill -TERM -f "go run ./src scan"
 
 # 等待5秒
 sleep 5
 
 # 检查是否还有进程
-REMAINING=$(pgrep -f "go run ./src/main.go")
+REMAINING=$(pgrep -f "go run ./src scan")
 if [ -z "$REMAINING" ]; then
     echo "✅ 所有进程已成功停止"
 else
     echo "⚠️  还有 $(echo "$REMAINING" | wc -l) 个进程未停止，强制终止..."
-    pkill -KILL -f "go run ./src/main.go"
+    pkill -KILL -f "go run ./src scan"
     sleep 2
     
     # 最终检查
-    FINAL_CHECK=$(pgrep -f "go run ./src/main.go")
+    FINAL_CHECK=$(pgrep -f "go run ./src scan")
     if [ -z "$FINAL_CHECK" ]; then
         echo "✅ 所有进程已强制停止"
     else
         echo "❌ 仍有进程无法停止，请手动检查"
         echo "剩余进程:"
-        ps aux | grep "go run ./src/main.go" | grep -v grep
+        ps aux | grep "go run ./src scan" | grep -v grep
     fi
 fi
 
@@ -43,13 +43,19 @@ for i in $(seq 0 $((PROCESS_COUNT - 1))); do
     # 计算端口：进程i使用端口 8000 + i
     PORT_START=$((8000 + i * PORTS_PER_PROCESS))
     
+    # 每个进程负责 [PROC_FROM, PROC_TO) 子范围，最后一个进程补齐余数
+    PROC_FROM=$((START_SLOT + i * BLOCKS_PER_PROCESS))
+    PROC_TO=$((PROC_FROM + BLOCKS_PER_PROCESS))
+    if [ $i -eq $((PROCESS_COUNT - 1)) ]; then
+        PROC_TO=$END_SLOT
+    fi
+
     echo "🎬 测试进程 $i: 预计处理 $BLOCKS_PER_PROCESS 个区块，$CYCLES_PER_PROCESS 个循环 -> 端口 $PORT_START"
     
     {
         PROCESS_START_TIME=$(date +%s)
         
-        # 使用新的参数格式：processId startSlot endSlot cycleSize batchSize portStart processCount
-        go run ./src/main.go $i $START_SLOT $END_SLOT $CYCLE_SIZE $BATCH_SIZE $PORT_START $PROCESS_COUNT
+        go run ./src scan --from $PROC_FROM --to $PROC_TO --cycle $CYCLE_SIZE --batch $BATCH_SIZE
         
         PROCESS_END_TIME=$(date +%s)
         PROCESS_DURATION=$((PROCESS_END_TIME - PROCESS_START_TIME))